@@ -0,0 +1,174 @@
+// Утиліта командного рядка turbo-signer.
+//
+//	turbo-signer audit-verify -log signing.log [-key-file chain.key | -key-env AUDIT_KEY]
+//	turbo-signer vector-gen -name order -algorithm hmac-sha256 -secret apy_secret \
+//		-params '{"timestamp":"1610612740000"}' [-profile venue -order timestamp] [-out vectors.yaml]
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/vectors"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdout io.Writer) error
+}
+
+var errUsage = errors.New("usage")
+
+func commands() []command {
+	return []command{
+		{"audit-verify", "verify hash chain of signing audit log", auditVerify},
+		{"vector-gen", "generate golden signature test vector", vectorGen},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		for _, command := range commands() {
+			if command.name != args[0] {
+				continue
+			}
+			if err := command.run(args[1:], stdout); err != nil {
+				if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+					fmt.Fprintf(stderr, "%s: %v\n", command.name, err)
+				}
+				return 1
+			}
+			return 0
+		}
+	}
+	fmt.Fprintln(stderr, "usage: turbo-signer <command> [flags]")
+	for _, command := range commands() {
+		fmt.Fprintf(stderr, "  %-14s %s\n", command.name, command.description)
+	}
+	return 2
+}
+
+func auditVerify(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("audit-verify", flag.ContinueOnError)
+	path := flags.String("log", "", "path to audit log")
+	keyFile := flags.String("key-file", "", "file with HMAC key of the chain")
+	keyEnv := flags.String("key-env", "", "environment variable with HMAC key of the chain")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		flags.Usage()
+		return errUsage
+	}
+	var key []byte
+	switch {
+	case *keyFile != "":
+		content, err := os.ReadFile(*keyFile)
+		if err != nil {
+			return err
+		}
+		// Перенесення рядка в кінці файлу (echo, редактори) не є частиною ключа
+		key = bytes.TrimRight(content, "\r\n")
+	case *keyEnv != "":
+		key = []byte(os.Getenv(*keyEnv))
+	}
+	count, err := signature.VerifyAuditLog(*path, key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: ok, %d records\n", *path, count)
+	return nil
+}
+
+// Генерація вектора з відомих коректних вхідних даних. З -out вектор дописується
+// до файлу (наявні вектори перевіряються), інакше файл з одним вектором - у stdout.
+func vectorGen(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("vector-gen", flag.ContinueOnError)
+	name := flags.String("name", "", "vector name")
+	algorithm := flags.String("algorithm", "", "hmac-sha256, rsa-v1_5-sha256, ed25519, ed25519ph or ed25519ctx")
+	profile := flags.String("profile", "", "registered venue profile")
+	apiKey := flags.String("api-key", "", "API key")
+	secret := flags.String("secret", "", "HMAC secret of test key")
+	publicKeyFile := flags.String("public-key", "", "PEM file with public key")
+	privateKeyFile := flags.String("private-key", "", "PEM file with private key")
+	context := flags.String("context", "", "context of ed25519ph and ed25519ctx")
+	params := flags.String("params", "", "params as JSON object")
+	order := flags.String("order", "", "comma separated order of params keys for venue profiles")
+	format := flags.String("format", "", "json or yaml, default by -out extension or json")
+	out := flags.String("out", "", "vectors file to append to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" || *algorithm == "" || *params == "" {
+		flags.Usage()
+		return errUsage
+	}
+	key := vectors.Key{APIKey: *apiKey, SecretKey: *secret, Context: *context}
+	for _, file := range []struct {
+		path  string
+		value *string
+	}{{*publicKeyFile, &key.PublicKey}, {*privateKeyFile, &key.PrivateKey}} {
+		if file.path == "" {
+			continue
+		}
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			return err
+		}
+		*file.value = string(content)
+	}
+	values, err := signature.ParseParams([]byte(*params))
+	if err != nil {
+		return err
+	}
+	vector := vectors.Vector{Name: *name, Algorithm: *algorithm, Profile: *profile, Key: key, Params: values.MustMap()}
+	if *order != "" {
+		vector.Order = strings.Split(*order, ",")
+	}
+	vector, err = vectors.Generate(vector)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = vectors.FormatJSON
+		if *out != "" {
+			*format = vectors.FileFormat(*out)
+		}
+	}
+	if *out == "" {
+		return vectors.Encode(stdout, []vectors.Vector{vector}, *format)
+	}
+
+	existing, err := vectors.Load(*out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := vectors.VerifyAll(existing); err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.Name == vector.Name {
+			return fmt.Errorf("vector %q already exists in %s", vector.Name, *out)
+		}
+	}
+	var buffer bytes.Buffer
+	if err := vectors.Encode(&buffer, append(existing, vector), *format); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buffer.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: added %q, %d vectors\n", *out, vector.Name, len(existing)+1)
+	return nil
+}
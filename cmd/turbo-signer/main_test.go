@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/fr0ster/turbo-signer/signature/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test 1: audit-verify reports valid and modified logs
+func TestAuditVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.log")
+	log, err := signature.OpenAuditLog(path, []byte("key"))
+	assert.Nil(t, err)
+	_, err = signature.NewSignAudit(signature.NewSignHMAC("apy_key", "apy_secret"), log, "", "test").SignParameters(nil)
+	assert.ErrorIs(t, err, signature.ErrNilParams)
+	signature.NewSignAudit(signature.NewSignHMAC("apy_key", "apy_secret"), log, "", "test").CreateSignature("timestamp=1")
+	assert.Nil(t, log.Close())
+	keyFile := filepath.Join(t.TempDir(), "chain.key")
+	assert.Nil(t, os.WriteFile(keyFile, []byte("key\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"audit-verify", "-log", path, "-key-file", keyFile}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "ok, 1 records")
+
+	t.Setenv("AUDIT_KEY", "wrong")
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"audit-verify", "-log", path, "-key-env", "AUDIT_KEY"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "audit log is modified")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "audit-verify")
+	assert.Equal(t, 1, run([]string{"audit-verify"}, &stdout, &stderr))
+}
+
+// Test 2: vector-gen prints a vector and appends it to a vectors file
+func TestVectorGen(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"vector-gen", "-name", "hmac", "-algorithm", "hmac-sha256", "-api-key", "apy_key", "-secret", "apy_secret",
+		"-params", `{"timestamp":1610612740000}`}
+	assert.Equal(t, 0, run(args, &stdout, &stderr), stderr.String())
+	generated, err := vectors.Decode(stdout.Bytes(), vectors.FormatJSON)
+	assert.Nil(t, err)
+	if assert.Len(t, generated, 1) {
+		assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", generated[0].Signature)
+	}
+
+	dir := t.TempDir()
+	publicKey := filepath.Join(dir, "public.pem")
+	privateKey := filepath.Join(dir, "private.pem")
+	assert.Nil(t, os.WriteFile(publicKey, []byte(signaturetest.Ed25519PublicKeyPEM), 0o600))
+	assert.Nil(t, os.WriteFile(privateKey, []byte(signaturetest.Ed25519PrivateKeyPEM), 0o600))
+	out := filepath.Join(dir, "vectors.yaml")
+	assert.Equal(t, 0, run(append(args, "-out", out), &stdout, &stderr), stderr.String())
+	assert.Equal(t, 0, run([]string{"vector-gen", "-name", "ed25519", "-algorithm", "ed25519", "-public-key", publicKey, "-private-key", privateKey,
+		"-params", `{"timestamp":1610612740000}`, "-out", out}, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), `added "ed25519", 2 vectors`)
+	generated, err = vectors.Load(out)
+	assert.Nil(t, err)
+	assert.Len(t, generated, 2)
+	assert.Nil(t, vectors.VerifyAll(generated))
+
+	stderr.Reset()
+	assert.Equal(t, 1, run(append(args, "-out", out), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "already exists")
+	assert.Equal(t, 1, run(append(args[:len(args)-1], `{"a":1,"a":2}`), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "duplicate key")
+	assert.Equal(t, 1, run([]string{"vector-gen", "-name", "x"}, &stdout, &stderr))
+
+	// Порядок параметрів для профілю біржі
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"vector-gen", "-name", "binance", "-algorithm", "hmac-sha256", "-secret", "apy_secret", "-profile", "binance",
+		"-params", `{"timestamp":1610612740000,"symbol":"BTCUSDT"}`, "-order", "timestamp,symbol"}, &stdout, &stderr), stderr.String())
+	generated, err = vectors.Decode(stdout.Bytes(), vectors.FormatJSON)
+	assert.Nil(t, err)
+	if assert.Len(t, generated, 1) {
+		assert.Equal(t, "timestamp=1610612740000&symbol=BTCUSDT", generated[0].Canonical)
+	}
+}
@@ -3,12 +3,16 @@ module github.com/fr0ster/turbo-signer
 go 1.22.5
 
 require (
+	filippo.io/edwards25519 v1.1.0
 	github.com/bitly/go-simplejson v0.5.1
+	github.com/miekg/pkcs11 v1.1.2
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/crypto v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -0,0 +1,393 @@
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Черга погодження чутливих запитів (виведення, керування API ключами) перед підписом.
+// Запит тримається у черзі, погоджувачі підписують ApprovalRequest.Message - ID
+// (SHA-256 канонічного рядка), час постановки, термін дії та випадковий nonce
+// цього екземпляра запиту - своїми ключами Ed25519 (Ed25519ctx з контекстом
+// ApprovalContext). Тож погодження не можна повторно використати для того самого
+// запиту, поставленого знову після спливу чи відхилення. Підпис біржі видається
+// лише після Required дійсних погоджень з M відомих погоджувачів до спливу TTL.
+// Кожна дія потрапляє до журналу: Audit тримає останні AuditLimit подій,
+// для постійного зберігання - ApprovalConfig.OnEvent. Відкритих запитів у черзі
+// не більше MaxPending, щоб потік чутливих запитів не вичерпав пам'ять.
+
+const (
+	ApprovalContext           = "turbo-signer approval"
+	defaultApprovalTTL        = 15 * time.Minute
+	defaultApprovalAuditLimit = 1000
+	defaultApprovalMaxPending = 1000
+)
+
+const (
+	ApprovalSubmitted = "submitted"
+	ApprovalApproved  = "approved"
+	ApprovalRejected  = "rejected"
+	ApprovalReleased  = "released"
+	ApprovalExpired   = "expired"
+	ApprovalInvalid   = "invalid" // погодження з недійсним підписом
+)
+
+var (
+	ErrApprovalPending   = errors.New("request is waiting for approval")
+	ErrApprovalNotFound  = errors.New("approval request not found")
+	ErrApprovalExpired   = errors.New("approval request is expired")
+	ErrApprovalReleased  = errors.New("approval request is already released")
+	ErrApprovalRejected  = errors.New("approval request is rejected")
+	ErrApproverUnknown   = errors.New("approver is unknown")
+	ErrApprovalDuplicate = errors.New("approver has already approved request")
+	ErrApprovalSignature = errors.New("approval signature is invalid")
+	ErrApprovalConfig    = errors.New("invalid approval config")
+	ErrApprovalQueueFull = errors.New("too many requests are waiting for approval")
+)
+
+type (
+	ApprovalConfig struct {
+		Approvers map[string]ed25519.PublicKey // ідентифікатор погоджувача -> публічний ключ
+		Required  int                          // N з M погоджень
+		TTL       time.Duration                // за замовчуванням 15 хвилин
+		// Чи потребує запит погодження, nil - усі запити
+		Sensitive func(params *Params) bool
+		// Необов'язковий обробник подій журналу. Викликається після звільнення черги,
+		// тож може звертатися до неї; події різних викликів можуть надходити з різних горутин.
+		OnEvent func(event ApprovalEvent)
+		// Кількість останніх подій у журналі Audit, за замовчуванням 1000
+		AuditLimit int
+		// Найбільша кількість відкритих запитів, за замовчуванням 1000
+		MaxPending int
+	}
+	ApprovalEvent struct {
+		Time      time.Time
+		RequestID string
+		Action    string // ApprovalSubmitted, ApprovalApproved, ApprovalRejected, ApprovalReleased, ApprovalExpired, ApprovalInvalid
+		Approver  string
+		Detail    string
+	}
+	ApprovalRequest struct {
+		ID        string
+		Canonical string
+		Submitted time.Time
+		Expires   time.Time
+		Nonce     string   // випадкове значення екземпляра запиту, hex
+		Approvals []string // ідентифікатори погоджувачів
+		Rejected  bool
+		Released  bool
+		Expired   bool
+		params    *Params
+	}
+	// Помилка SignParameters для запиту, що чекає погодження
+	ApprovalPendingError struct {
+		ID string
+	}
+	ApprovalQueue struct {
+		sign     Sign
+		config   ApprovalConfig
+		mutex    sync.Mutex
+		requests map[string]*ApprovalRequest
+		audit    []ApprovalEvent
+		events   []ApprovalEvent // події для OnEvent, що передаються після звільнення м'ютекса
+		now      func() time.Time
+	}
+)
+
+func (err *ApprovalPendingError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrApprovalPending, err.ID)
+}
+
+func (err *ApprovalPendingError) Unwrap() error {
+	return ErrApprovalPending
+}
+
+// Рядок, який підписує погоджувач: ID, час постановки та термін дії (Unix наносекунди) і nonce.
+// Погоджувач перевіряє, що ID - SHA-256 від Canonical, який він погоджує.
+func (request ApprovalRequest) Message() string {
+	return request.ID + "\n" + strconv.FormatInt(request.Submitted.UnixNano(), 10) + "\n" +
+		strconv.FormatInt(request.Expires.UnixNano(), 10) + "\n" + request.Nonce
+}
+
+// Функція для створення погодження ключем погоджувача, message - ApprovalRequest.Message,
+// результат у Base64
+func SignApproval(privateKey ed25519.PrivateKey, message string) (string, error) {
+	options := Ed25519ctxOptions(ApprovalContext)
+	signature, err := privateKey.Sign(nil, []byte(message), &options)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func NewApprovalQueue(sign Sign, config ApprovalConfig) (*ApprovalQueue, error) {
+	if config.Required < 1 || config.Required > len(config.Approvers) {
+		return nil, fmt.Errorf("%w: required %d of %d approvers", ErrApprovalConfig, config.Required, len(config.Approvers))
+	}
+	for id, publicKey := range config.Approvers {
+		if len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: bad public key of %s", ErrApprovalConfig, id)
+		}
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultApprovalTTL
+	}
+	if config.AuditLimit <= 0 {
+		config.AuditLimit = defaultApprovalAuditLimit
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = defaultApprovalMaxPending
+	}
+	return &ApprovalQueue{
+		sign:     sign,
+		config:   config,
+		requests: make(map[string]*ApprovalRequest),
+		now:      time.Now,
+	}, nil
+}
+
+// Функція для заміни годинника, за яким рахується термін дії запитів
+func (queue *ApprovalQueue) SetClock(now func() time.Time) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.now = now
+}
+
+// Функція для постановки запиту у чергу, ідентифікатор - SHA-256 канонічного рядка.
+// Повторна постановка відкритого запиту повертає той самий ідентифікатор, а після
+// видачі, відхилення чи спливу створюється новий екземпляр з новим nonce.
+// Понад MaxPending відкритих запитів повертається ErrApprovalQueueFull.
+func (queue *ApprovalQueue) Submit(params *Params) (string, error) {
+	if params == nil {
+		return "", ErrNilParams
+	}
+	queue.mutex.Lock()
+	defer queue.unlock()
+	queue.expire()
+	params = params.Clone().Del("signature")
+	canonical := params.Encode()
+	digest := sha256.Sum256([]byte(canonical))
+	id := hex.EncodeToString(digest[:])
+	if request, ok := queue.requests[id]; ok && !request.Released && !request.Rejected && !request.Expired {
+		return id, nil
+	}
+	if queue.pending() >= queue.config.MaxPending {
+		return "", fmt.Errorf("%w: %d", ErrApprovalQueueFull, queue.config.MaxPending)
+	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", err
+	}
+	now := queue.now()
+	queue.requests[id] = &ApprovalRequest{
+		ID:        id,
+		Canonical: canonical,
+		Submitted: now,
+		Expires:   now.Add(queue.config.TTL),
+		Nonce:     hex.EncodeToString(nonce[:]),
+		params:    params,
+	}
+	queue.record(ApprovalEvent{Time: now, RequestID: id, Action: ApprovalSubmitted})
+	return id, nil
+}
+
+// Функція для погодження запиту, signature - результат SignApproval від погоджувача
+func (queue *ApprovalQueue) Approve(id, approver, signature string) error {
+	queue.mutex.Lock()
+	defer queue.unlock()
+	request, err := queue.open(id)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := queue.config.Approvers[approver]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrApproverUnknown, approver)
+	}
+	for _, approved := range request.Approvals {
+		if approved == approver {
+			return fmt.Errorf("%w: %s", ErrApprovalDuplicate, approver)
+		}
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	options := Ed25519ctxOptions(ApprovalContext)
+	if err != nil || ed25519.VerifyWithOptions(publicKey, []byte(request.Message()), signatureBytes, &options) != nil {
+		queue.record(ApprovalEvent{Time: queue.now(), RequestID: id, Action: ApprovalInvalid, Approver: approver})
+		return fmt.Errorf("%w: %s", ErrApprovalSignature, approver)
+	}
+	request.Approvals = append(request.Approvals, approver)
+	queue.record(ApprovalEvent{Time: queue.now(), RequestID: id, Action: ApprovalApproved, Approver: approver})
+	return nil
+}
+
+// Функція для відхилення запиту погоджувачем, відхилений запит не може бути виданий
+func (queue *ApprovalQueue) Reject(id, approver, reason string) error {
+	queue.mutex.Lock()
+	defer queue.unlock()
+	request, err := queue.open(id)
+	if err != nil {
+		return err
+	}
+	if _, ok := queue.config.Approvers[approver]; !ok {
+		return fmt.Errorf("%w: %s", ErrApproverUnknown, approver)
+	}
+	request.Rejected = true
+	queue.record(ApprovalEvent{Time: queue.now(), RequestID: id, Action: ApprovalRejected, Approver: approver, Detail: reason})
+	return nil
+}
+
+// Функція для отримання підписаних параметрів після достатньої кількості погоджень.
+// Запит видається один раз.
+func (queue *ApprovalQueue) Release(id string) (*Params, error) {
+	queue.mutex.Lock()
+	defer queue.unlock()
+	request, err := queue.open(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Approvals) < queue.config.Required {
+		return nil, &ApprovalPendingError{ID: id}
+	}
+	signed, err := SignParams(request.params, queue.sign)
+	if err != nil {
+		return nil, err
+	}
+	request.Released = true
+	queue.record(ApprovalEvent{Time: queue.now(), RequestID: id, Action: ApprovalReleased})
+	return signed, nil
+}
+
+// Функція для отримання копії запиту з черги
+func (queue *ApprovalQueue) Request(id string) (ApprovalRequest, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	request, ok := queue.requests[id]
+	if !ok {
+		return ApprovalRequest{}, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+	copied := *request
+	copied.Approvals = append([]string(nil), request.Approvals...)
+	copied.params = nil
+	return copied, nil
+}
+
+// Функція для отримання копії журналу, не більше AuditLimit останніх подій
+func (queue *ApprovalQueue) Audit() []ApprovalEvent {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return append([]ApprovalEvent(nil), queue.audit...)
+}
+
+// Підпис рядка чутливого запиту без погодження неможливий, повертається порожній рядок
+func (queue *ApprovalQueue) CreateSignature(queryString string) string {
+	values, err := url.ParseQuery(queryString)
+	if err != nil || queue.sensitive(ParamsFromValues(values)) {
+		return ""
+	}
+	return queue.sign.CreateSignature(queryString)
+}
+
+// Функція для створення підпису з помилкою: чутливий запит ставиться у чергу
+// і повертає *ApprovalPendingError, як у SignParameters
+func (queue *ApprovalQueue) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	values, err := url.ParseQuery(queryString)
+	if err != nil {
+		return "", err
+	}
+	if params := ParamsFromValues(values); queue.sensitive(params) {
+		id, err := queue.Submit(params)
+		if err != nil {
+			return "", err
+		}
+		return "", &ApprovalPendingError{ID: id}
+	}
+	return createSignature(ctx, queue.sign, queryString)
+}
+
+func (queue *ApprovalQueue) ValidateSignature(message, signature string) bool {
+	return queue.sign.ValidateSignature(message, signature)
+}
+
+func (queue *ApprovalQueue) GetAPIKey() string {
+	return queue.sign.GetAPIKey()
+}
+
+func (queue *ApprovalQueue) sensitive(params *Params) bool {
+	return queue.config.Sensitive == nil || queue.config.Sensitive(params)
+}
+
+// Запит, який ще можна погоджувати чи видавати
+func (queue *ApprovalQueue) open(id string) (*ApprovalRequest, error) {
+	queue.expire()
+	request, ok := queue.requests[id]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	case request.Released:
+		return nil, fmt.Errorf("%w: %s", ErrApprovalReleased, id)
+	case request.Rejected:
+		return nil, fmt.Errorf("%w: %s", ErrApprovalRejected, id)
+	case request.Expired:
+		return nil, fmt.Errorf("%w: %s", ErrApprovalExpired, id)
+	}
+	return request, nil
+}
+
+// Прострочені запити позначаються у журналі, а видаляються з черги ще через TTL,
+// щоб до того повертати ErrApprovalExpired замість ErrApprovalNotFound
+func (queue *ApprovalQueue) expire() {
+	now := queue.now()
+	for id, request := range queue.requests {
+		if now.Before(request.Expires) {
+			continue
+		}
+		if !request.Expired && !request.Released && !request.Rejected {
+			request.Expired = true
+			queue.record(ApprovalEvent{Time: now, RequestID: id, Action: ApprovalExpired})
+		}
+		if !now.Before(request.Expires.Add(queue.config.TTL)) {
+			delete(queue.requests, id)
+		}
+	}
+}
+
+// Кількість відкритих запитів
+func (queue *ApprovalQueue) pending() (count int) {
+	for _, request := range queue.requests {
+		if !request.Released && !request.Rejected && !request.Expired {
+			count++
+		}
+	}
+	return
+}
+
+func (queue *ApprovalQueue) record(event ApprovalEvent) {
+	queue.audit = append(queue.audit, event)
+	if len(queue.audit) > queue.config.AuditLimit {
+		// Найстаріші події відкидаються, масив перевиділяється при наступному рості
+		queue.audit = queue.audit[len(queue.audit)-queue.config.AuditLimit:]
+	}
+	if queue.config.OnEvent != nil {
+		queue.events = append(queue.events, event)
+	}
+}
+
+// Звільнення м'ютекса з передачею накопичених подій OnEvent вже поза ним:
+// обробник, що пише у повільне сховище чи звертається до черги, не блокує її
+func (queue *ApprovalQueue) unlock() {
+	events := queue.events
+	queue.events = nil
+	queue.mutex.Unlock()
+	for _, event := range events {
+		queue.config.OnEvent(event)
+	}
+}
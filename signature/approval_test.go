@@ -0,0 +1,260 @@
+package signature_test
+
+import (
+	"crypto/ed25519"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type approver struct {
+	id         string
+	privateKey ed25519.PrivateKey
+}
+
+func testApprovalQueue(t *testing.T, required int) (*signature.ApprovalQueue, []approver, *time.Time) {
+	approvers := make([]approver, 3)
+	keys := make(map[string]ed25519.PublicKey)
+	for i, id := range []string{"alice", "bob", "carol"} {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i + 1)
+		approvers[i] = approver{id: id, privateKey: ed25519.NewKeyFromSeed(seed)}
+		keys[id] = approvers[i].privateKey.Public().(ed25519.PublicKey)
+	}
+	queue, err := signature.NewApprovalQueue(signature.NewSignHMAC("apy_key", "apy_secret"), signature.ApprovalConfig{
+		Approvers: keys,
+		Required:  required,
+		TTL:       10 * time.Minute,
+		Sensitive: func(params *signature.Params) bool {
+			_, ok := params.Get("address")
+			return ok
+		},
+	})
+	assert.Nil(t, err)
+	now := time.Unix(1610612740, 0)
+	queue.SetClock(func() time.Time { return now })
+	return queue, approvers, &now
+}
+
+func withdrawParams() *signature.Params {
+	return signature.NewParams().
+		SetString("coin", "BTC").
+		SetString("address", "bc1qtrusted").
+		SetString("amount", "0.5").
+		SetInt64("timestamp", 1610612740000)
+}
+
+func approve(t *testing.T, queue *signature.ApprovalQueue, id string, approver approver) error {
+	request, err := queue.Request(id)
+	assert.Nil(t, err)
+	approval, err := signature.SignApproval(approver.privateKey, request.Message())
+	assert.Nil(t, err)
+	return queue.Approve(id, approver.id, approval)
+}
+
+// Test 1: Signature is released after 2 of 3 approvals
+func TestApprovalQueueRelease(t *testing.T) {
+	queue, approvers, _ := testApprovalQueue(t, 2)
+	id, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	again, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	assert.Equal(t, id, again)
+
+	_, err = queue.Release(id)
+	assert.ErrorIs(t, err, signature.ErrApprovalPending)
+	assert.Nil(t, approve(t, queue, id, approvers[0]))
+	assert.ErrorIs(t, approve(t, queue, id, approvers[0]), signature.ErrApprovalDuplicate)
+	_, err = queue.Release(id)
+	assert.ErrorIs(t, err, signature.ErrApprovalPending)
+	assert.Nil(t, approve(t, queue, id, approvers[2]))
+
+	signed, err := queue.Release(id)
+	assert.Nil(t, err)
+	assert.True(t, signature.ValidateParams(signed, queue))
+	_, err = queue.Release(id)
+	assert.ErrorIs(t, err, signature.ErrApprovalReleased)
+
+	var actions []string
+	for _, event := range queue.Audit() {
+		assert.Equal(t, id, event.RequestID)
+		actions = append(actions, event.Action+":"+event.Approver)
+	}
+	assert.Equal(t, []string{"submitted:", "approved:alice", "approved:carol", "released:"}, actions)
+}
+
+// Test 2: Invalid approvals
+func TestApprovalQueueInvalid(t *testing.T) {
+	queue, approvers, _ := testApprovalQueue(t, 2)
+	id, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	// Погодження канонічного рядка замість Message
+	other, err := signature.SignApproval(approvers[1].privateKey, withdrawParams().Encode())
+	assert.Nil(t, err)
+	assert.ErrorIs(t, queue.Approve(id, "bob", other), signature.ErrApprovalSignature)
+	// Погодження ключем іншого погоджувача
+	request, err := queue.Request(id)
+	assert.Nil(t, err)
+	stolen, err := signature.SignApproval(approvers[0].privateKey, request.Message())
+	assert.Nil(t, err)
+	assert.ErrorIs(t, queue.Approve(id, "bob", stolen), signature.ErrApprovalSignature)
+	assert.ErrorIs(t, queue.Approve(id, "mallory", stolen), signature.ErrApproverUnknown)
+	assert.ErrorIs(t, queue.Approve("unknown", "alice", stolen), signature.ErrApprovalNotFound)
+
+	assert.Nil(t, queue.Reject(id, "carol", "unknown address"))
+	assert.ErrorIs(t, approve(t, queue, id, approvers[0]), signature.ErrApprovalRejected)
+
+	_, err = signature.NewApprovalQueue(queue, signature.ApprovalConfig{Required: 1})
+	assert.ErrorIs(t, err, signature.ErrApprovalConfig)
+}
+
+// Test 3: Requests expire
+func TestApprovalQueueExpiry(t *testing.T) {
+	queue, approvers, now := testApprovalQueue(t, 1)
+	id, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	*now = now.Add(10 * time.Minute)
+	assert.ErrorIs(t, approve(t, queue, id, approvers[0]), signature.ErrApprovalExpired)
+	_, err = queue.Release(id)
+	assert.ErrorIs(t, err, signature.ErrApprovalExpired)
+	events := queue.Audit()
+	assert.Equal(t, signature.ApprovalExpired, events[len(events)-1].Action)
+
+	*now = now.Add(10 * time.Minute)
+	_, err = queue.Release(id)
+	assert.ErrorIs(t, err, signature.ErrApprovalNotFound)
+}
+
+// Test 4: SignParameters holds sensitive requests only
+func TestApprovalQueueSignParameters(t *testing.T) {
+	queue, approvers, _ := testApprovalQueue(t, 1)
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := queue.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, queue.ValidateSignatureParams(signed))
+
+	params.Set("address", "bc1qtrusted")
+	_, err = queue.SignParameters(params)
+	var pending *signature.ApprovalPendingError
+	assert.ErrorAs(t, err, &pending)
+	assert.ErrorIs(t, err, signature.ErrApprovalPending)
+	assert.Empty(t, queue.CreateSignature("address=bc1qtrusted&timestamp=1610612740000"))
+
+	assert.Nil(t, approve(t, queue, pending.ID, approvers[1]))
+	released, err := queue.Release(pending.ID)
+	assert.Nil(t, err)
+	expected, err := signature.NewSignHMAC("apy_key", "apy_secret").SignParameters(params)
+	assert.Nil(t, err)
+	value, _ := released.Get("signature")
+	assert.Equal(t, expected.Get("signature").MustString(), value)
+}
+
+// Test 5: Approvals of an expired or rejected instance do not approve it again
+func TestApprovalQueueReplay(t *testing.T) {
+	queue, approvers, now := testApprovalQueue(t, 1)
+	id, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	first, err := queue.Request(id)
+	assert.Nil(t, err)
+	approval, err := signature.SignApproval(approvers[0].privateKey, first.Message())
+	assert.Nil(t, err)
+
+	// Після спливу той самий запит ставиться знову з тим самим ID, але новим nonce
+	*now = now.Add(20 * time.Minute)
+	again, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	assert.Equal(t, id, again)
+	second, err := queue.Request(id)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first.Nonce, second.Nonce)
+	assert.ErrorIs(t, queue.Approve(id, "alice", approval), signature.ErrApprovalSignature)
+
+	// Погодження відхиленого екземпляра
+	assert.Nil(t, queue.Reject(id, "bob", "wrong amount"))
+	approval, err = signature.SignApproval(approvers[0].privateKey, second.Message())
+	assert.Nil(t, err)
+	_, err = queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	assert.ErrorIs(t, queue.Approve(id, "alice", approval), signature.ErrApprovalSignature)
+	assert.Nil(t, approve(t, queue, id, approvers[0]))
+}
+
+// Test 6: Audit keeps the last AuditLimit events
+func TestApprovalQueueAuditLimit(t *testing.T) {
+	var events int
+	queue, err := signature.NewApprovalQueue(signature.NewSignHMAC("apy_key", "apy_secret"), signature.ApprovalConfig{
+		Approvers:  map[string]ed25519.PublicKey{"alice": make(ed25519.PublicKey, ed25519.PublicKeySize)},
+		Required:   1,
+		AuditLimit: 3,
+		OnEvent:    func(signature.ApprovalEvent) { events++ },
+	})
+	assert.Nil(t, err)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id, err := queue.Submit(withdrawParams().SetInt64("timestamp", int64(i)))
+		assert.Nil(t, err)
+		ids = append(ids, id)
+	}
+	audit := queue.Audit()
+	assert.Len(t, audit, 3)
+	assert.Equal(t, ids[2], audit[0].RequestID)
+	assert.Equal(t, 5, events)
+}
+
+// Test 7: OnEvent is called outside the queue lock and may use the queue
+func TestApprovalQueueOnEventReentry(t *testing.T) {
+	var actions []string
+	var queue *signature.ApprovalQueue
+	queue, err := signature.NewApprovalQueue(signature.NewSignHMAC("apy_key", "apy_secret"), signature.ApprovalConfig{
+		Approvers: map[string]ed25519.PublicKey{"alice": make(ed25519.PublicKey, ed25519.PublicKeySize)},
+		Required:  1,
+		OnEvent: func(event signature.ApprovalEvent) {
+			// Звернення до черги з обробника не блокується
+			request, err := queue.Request(event.RequestID)
+			assert.Nil(t, err)
+			actions = append(actions, event.Action+" "+strconv.FormatBool(request.Rejected))
+			assert.NotEmpty(t, queue.Audit())
+		},
+	})
+	assert.Nil(t, err)
+	id, err := queue.Submit(withdrawParams())
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Reject(id, "alice", "unknown address"))
+	assert.Equal(t, []string{"submitted false", "rejected true"}, actions)
+}
+
+// Test 8: Open requests are limited by MaxPending
+func TestApprovalQueueMaxPending(t *testing.T) {
+	queue, err := signature.NewApprovalQueue(signature.NewSignHMAC("apy_key", "apy_secret"), signature.ApprovalConfig{
+		Approvers:  map[string]ed25519.PublicKey{"alice": make(ed25519.PublicKey, ed25519.PublicKeySize)},
+		Required:   1,
+		TTL:        time.Minute,
+		MaxPending: 2,
+	})
+	assert.Nil(t, err)
+	now := time.Unix(1610612740, 0)
+	queue.SetClock(func() time.Time { return now })
+	first, err := queue.Submit(withdrawParams().SetInt64("timestamp", 1))
+	assert.Nil(t, err)
+	_, err = queue.Submit(withdrawParams().SetInt64("timestamp", 2))
+	assert.Nil(t, err)
+	_, err = queue.Submit(withdrawParams().SetInt64("timestamp", 3))
+	assert.ErrorIs(t, err, signature.ErrApprovalQueueFull)
+	// Повторна постановка відкритого запиту не займає нового місця
+	id, err := queue.Submit(withdrawParams().SetInt64("timestamp", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, first, id)
+
+	// Відхилені та прострочені запити місця не займають
+	assert.Nil(t, queue.Reject(first, "alice", ""))
+	_, err = queue.Submit(withdrawParams().SetInt64("timestamp", 3))
+	assert.Nil(t, err)
+	now = now.Add(time.Minute)
+	_, err = queue.Submit(withdrawParams().SetInt64("timestamp", 4))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,399 @@
+package signature
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Журнал аудиту підписів: кожен виклик CreateSignature/SignParameters після підпису
+// записується з його результатом рядком JSON у файл, відкритий лише на дописування. Записи утворюють
+// ланцюжок: hash = H(prev || запис без hash), де H - SHA-256 або HMAC-SHA256
+// з ключем журналу (тоді переписати ланцюжок без ключа неможливо).
+// Після кожного запису оновлюється файл <log>.head з номером та хешем останнього
+// запису (з ключем - і HMAC цих значень), за яким VerifyAuditLog виявляє обрізання
+// хвоста журналу. Збій посеред запису лишає неповний останній рядок або head,
+// що відстає на один запис; OpenAuditLog відкидає такий рядок чи оновлює head.
+// Канонічний рядок до журналу не потрапляє - лише його SHA-256, а мітка
+// виклику та помилки проходять через редагування секретів.
+
+const auditHeadSuffix = ".head"
+
+var (
+	ErrAuditTampered  = errors.New("audit log is modified")
+	ErrAuditTruncated = errors.New("audit log is truncated")
+)
+
+// Значення ключів, схожих на секрети, у рядках виду key=value, key: value або "key":"value"
+var auditSecretPattern = regexp.MustCompile(`(?i)((?:secret|password|passphrase|token|signature|private[_-]?key|api[_-]?secret)["']?\s*[=:]\s*["']?)[^\s&,;"']+`)
+
+type (
+	AuditRecord struct {
+		Seq             uint64    `json:"seq"`
+		Time            time.Time `json:"time"`
+		KeyID           string    `json:"key_id"`
+		Algorithm       string    `json:"algorithm"`
+		Operation       string    `json:"operation"` // CreateSignature або SignParameters
+		CanonicalSHA256 string    `json:"canonical_sha256"`
+		Caller          string    `json:"caller,omitempty"`
+		Error           string    `json:"error,omitempty"`
+		Prev            string    `json:"prev"`
+		Hash            string    `json:"hash,omitempty"`
+	}
+	auditHead struct {
+		Seq  uint64 `json:"seq"`
+		Hash string `json:"hash"`
+		MAC  string `json:"mac,omitempty"` // HMAC-SHA256 seq та hash ключем журналу
+	}
+	// Стан журналу на диску
+	auditState struct {
+		last auditHead
+		prev string // Prev останнього запису
+		size int64  // кінець останнього повного запису
+		// Неповний останній рядок або head, що відстає на один запис
+		partial, headBehind bool
+	}
+	AuditLog struct {
+		path  string
+		key   []byte
+		mutex sync.Mutex
+		file  *os.File
+		seq   uint64
+		prev  string
+		now   func() time.Time
+	}
+	// Підписувач, що записує кожен підпис до журналу аудиту
+	SignAudit struct {
+		sign   Sign
+		log    *AuditLog
+		keyID  string
+		caller string
+	}
+)
+
+// Функція для відкриття (або створення) журналу. Наявний журнал перевіряється,
+// нові записи продовжують його ланцюжок. key - необов'язковий ключ HMAC ланцюжка.
+func OpenAuditLog(path string, key []byte) (*AuditLog, error) {
+	state, err := verifyAuditLog(path, key, false, true)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	// Відновлення після збою: неповний рядок не має дійсного хешу, відкидається
+	if state.partial {
+		if err := os.Truncate(path, state.size); err != nil {
+			return nil, err
+		}
+	}
+	if state.headBehind {
+		if err := writeAuditHead(path, key, state.last); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{path: path, key: key, file: file, seq: state.last.Seq, prev: state.last.Hash, now: time.Now}, nil
+}
+
+// Функція для заміни годинника, яким позначаються записи
+func (log *AuditLog) SetClock(now func() time.Time) {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	log.now = now
+}
+
+// Функція для дописування запису, Seq, Time (якщо нульовий), Prev та Hash заповнюються журналом
+func (log *AuditLog) Append(record AuditRecord) (AuditRecord, error) {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	if log.file == nil {
+		return record, os.ErrClosed
+	}
+	record.Seq = log.seq + 1
+	if record.Time.IsZero() {
+		record.Time = log.now()
+	}
+	record.Time = record.Time.UTC()
+	record.Caller = RedactSecrets(record.Caller)
+	record.Error = RedactSecrets(record.Error)
+	record.Prev = log.prev
+	record.Hash = ""
+	hash, err := auditHash(log.key, record)
+	if err != nil {
+		return record, err
+	}
+	record.Hash = hash
+	line, err := json.Marshal(record)
+	if err != nil {
+		return record, err
+	}
+	if _, err := log.file.Write(append(line, '\n')); err != nil {
+		return record, err
+	}
+	if err := log.file.Sync(); err != nil {
+		return record, err
+	}
+	if err := writeAuditHead(log.path, log.key, auditHead{Seq: record.Seq, Hash: record.Hash}); err != nil {
+		return record, err
+	}
+	log.seq, log.prev = record.Seq, record.Hash
+	return record, nil
+}
+
+func (log *AuditLog) Close() error {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	if log.file == nil {
+		return nil
+	}
+	err := log.file.Close()
+	log.file = nil
+	return err
+}
+
+// Функція для перевірки журналу: ланцюжок хешів, неперервність номерів
+// та відповідність останнього запису файлу <log>.head (з ключем - і його HMAC).
+// Повертає кількість записів.
+func VerifyAuditLog(path string, key []byte) (int, error) {
+	state, err := verifyAuditLog(path, key, true, false)
+	return int(state.last.Seq), err
+}
+
+// recover - допустити сліди збою посеред запису (неповний останній рядок,
+// head на один запис позаду), їх виправляє OpenAuditLog
+func verifyAuditLog(path string, key []byte, requireHead, recover bool) (auditState, error) {
+	var state auditState
+	last := &state.last
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Видалений журнал з наявним head - це обрізання до нуля
+		if _, headErr := os.Stat(path + auditHeadSuffix); headErr == nil {
+			return state, fmt.Errorf("%w: log file is missing", ErrAuditTruncated)
+		}
+	}
+	if err != nil {
+		return state, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if !recover {
+					return state, fmt.Errorf("%w: incomplete record after seq %d", ErrAuditTruncated, last.Seq)
+				}
+				state.partial = true
+			}
+			break
+		}
+		if err != nil {
+			return state, err
+		}
+		var record AuditRecord
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return state, fmt.Errorf("%w: bad record after seq %d: %v", ErrAuditTampered, last.Seq, err)
+		}
+		if record.Seq != last.Seq+1 {
+			return state, fmt.Errorf("%w: seq %d follows %d", ErrAuditTampered, record.Seq, last.Seq)
+		}
+		if record.Prev != last.Hash {
+			return state, fmt.Errorf("%w: broken chain at seq %d", ErrAuditTampered, record.Seq)
+		}
+		stored := record.Hash
+		record.Hash = ""
+		expected, err := auditHash(key, record)
+		if err != nil {
+			return state, err
+		}
+		if !hmac.Equal([]byte(expected), []byte(stored)) {
+			return state, fmt.Errorf("%w: hash mismatch at seq %d", ErrAuditTampered, record.Seq)
+		}
+		*last = auditHead{Seq: record.Seq, Hash: stored}
+		state.prev = record.Prev
+		state.size += int64(len(line))
+	}
+
+	content, err := os.ReadFile(path + auditHeadSuffix)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !requireHead && last.Seq == 0:
+		return state, nil
+	case errors.Is(err, os.ErrNotExist):
+		return state, fmt.Errorf("%w: head file is missing", ErrAuditTruncated)
+	case err != nil:
+		return state, err
+	}
+	var head auditHead
+	if err := json.Unmarshal(content, &head); err != nil {
+		return state, fmt.Errorf("%w: bad head file: %v", ErrAuditTampered, err)
+	}
+	if len(key) > 0 && !hmac.Equal([]byte(head.MAC), []byte(auditHeadMAC(key, head.Seq, head.Hash))) {
+		return state, fmt.Errorf("%w: head MAC mismatch", ErrAuditTampered)
+	}
+	if head.Seq > last.Seq {
+		return state, fmt.Errorf("%w: %d of %d records", ErrAuditTruncated, last.Seq, head.Seq)
+	}
+	if recover && head.Seq+1 == last.Seq && head.Hash == state.prev {
+		// Запис дописано, а head ще ні: запис має дійсний хеш ланцюжка
+		state.headBehind = true
+		return state, nil
+	}
+	if head.Seq != last.Seq || head.Hash != last.Hash {
+		return state, fmt.Errorf("%w: head does not match last record", ErrAuditTampered)
+	}
+	return state, nil
+}
+
+func auditHash(key []byte, record AuditRecord) (string, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write([]byte(record.Prev))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HMAC head ключем журналу: без ключа head не підробити під обрізаний журнал
+func auditHeadMAC(key []byte, seq uint64, hash string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte("turbo-signer audit head\n" + strconv.FormatUint(seq, 10) + "\n" + hash))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Запис через тимчасовий файл та перейменування, щоб head не був частковим
+func writeAuditHead(path string, key []byte, head auditHead) error {
+	head.MAC = ""
+	if len(key) > 0 {
+		head.MAC = auditHeadMAC(key, head.Seq, head.Hash)
+	}
+	content, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	temporary := path + auditHeadSuffix + ".tmp"
+	if err := os.WriteFile(temporary, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(temporary, path+auditHeadSuffix)
+}
+
+// Функція для маскування значень секретів у довільному тексті
+func RedactSecrets(text string) string {
+	return auditSecretPattern.ReplaceAllString(text, "${1}"+redacted)
+}
+
+// Назва алгоритму підписувача: імена RFC 9421 для вбудованих підписувачів,
+// Algorithm() для підписувачів, що його реалізують, інакше - ім'я типу
+func Algorithm(sign Sign) string {
+	if ed25519Sign, ok := sign.(*SignEd25519); ok && ed25519Sign.options != nil {
+		if ed25519Sign.options.Hash == crypto.SHA512 {
+			return "ed25519ph"
+		}
+		return "ed25519ctx"
+	}
+	if algorithm := httpSigDefaultAlgorithm(sign); algorithm != "" {
+		return algorithm
+	}
+	if named, ok := sign.(interface{ Algorithm() string }); ok {
+		return named.Algorithm()
+	}
+	return fmt.Sprintf("%T", sign)
+}
+
+// keyID - ідентифікатор ключа у журналі, порожній - API ключ підписувача
+func NewSignAudit(sign Sign, log *AuditLog, keyID, caller string) *SignAudit {
+	if keyID == "" {
+		keyID = sign.GetAPIKey()
+	}
+	return &SignAudit{sign: sign, log: log, keyID: keyID, caller: caller}
+}
+
+// Функція для отримання підписувача з іншою міткою виклику та тим самим журналом
+func (sign *SignAudit) WithCaller(caller string) *SignAudit {
+	clone := *sign
+	clone.caller = caller
+	return &clone
+}
+
+// Якщо запис до журналу не вдався, підпис не видається
+func (sign *SignAudit) CreateSignature(queryString string) string {
+	signature := sign.sign.CreateSignature(queryString)
+	var err error
+	if signature == "" {
+		err = ErrEmptySignature
+	}
+	if sign.record("CreateSignature", queryString, err) != nil {
+		return ""
+	}
+	return signature
+}
+
+// Функція для створення підпису з помилкою запису до журналу або підписувача
+func (sign *SignAudit) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	signature, err := createSignature(ctx, sign.sign, queryString)
+	if err := sign.record("CreateSignature", queryString, err); err != nil {
+		return "", err
+	}
+	return signature, nil
+}
+
+func (sign *SignAudit) ValidateSignature(message, signature string) bool {
+	return sign.sign.ValidateSignature(message, signature)
+}
+
+func (sign *SignAudit) GetAPIKey() string {
+	return sign.sign.GetAPIKey()
+}
+
+// Запис виклику з його результатом після підпису, помилка підписувача потрапляє
+// до журналу відредагованою. Повертається помилка підписувача або запису до журналу:
+// без запису підпис не видається.
+func (sign *SignAudit) record(operation, canonical string, signErr error) error {
+	digest := sha256.Sum256([]byte(canonical))
+	record := AuditRecord{
+		KeyID:           sign.keyID,
+		Algorithm:       Algorithm(sign.sign),
+		Operation:       operation,
+		CanonicalSHA256: hex.EncodeToString(digest[:]),
+		Caller:          sign.caller,
+	}
+	if signErr != nil {
+		record.Error = signErr.Error()
+	}
+	if _, err := sign.log.Append(record); err != nil {
+		return errors.Join(signErr, fmt.Errorf("error writing audit log: %w", err))
+	}
+	return signErr
+}
+
+// Форматування без ключа ланцюжка
+func (log *AuditLog) String() string {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+	return "AuditLog{path: " + log.path + ", seq: " + strconv.FormatUint(log.seq, 10) + "}"
+}
+func (log *AuditLog) GoString() string { return log.String() }
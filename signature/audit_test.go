@@ -0,0 +1,241 @@
+package signature_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+var auditKey = []byte("audit chain key")
+
+func writeAuditLog(t *testing.T, records int) string {
+	path := filepath.Join(t.TempDir(), "signing.log")
+	log, err := signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	log.SetClock(func() time.Time { return time.Unix(1610612740, 0) })
+	sign := signature.NewSignAudit(signature.NewSignHMAC("apy_key", "apy_secret"), log, "hmac-main", "strategy-1")
+	for i := 0; i < records; i++ {
+		params := simplejson.New()
+		params.Set("timestamp", 1610612740000+i)
+		_, err := sign.SignParameters(params)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, log.Close())
+	return path
+}
+
+// Test 1: Signing calls are recorded in a verifiable chain
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.log")
+	log, err := signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	sign := signature.NewSignAudit(signature.NewSignHMAC("apy_key", "apy_secret"), log, "", "strategy-1")
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", sign.CreateSignature("timestamp=1610612740000"))
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.WithCaller("token=abc123 strategy-2").SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.Nil(t, log.Close())
+
+	count, err := signature.VerifyAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	digest := sha256.Sum256([]byte("timestamp=1610612740000"))
+	assert.Contains(t, string(content), hex.EncodeToString(digest[:]))
+	assert.Contains(t, string(content), `"algorithm":"hmac-sha256"`)
+	assert.Contains(t, string(content), `"key_id":"apy_key"`)
+	assert.Contains(t, string(content), `"operation":"SignParameters"`)
+	assert.Contains(t, string(content), `"caller":"token=[REDACTED] strategy-2"`)
+	assert.NotContains(t, string(content), "abc123")
+	assert.NotContains(t, string(content), "timestamp=")
+	assert.NotContains(t, fmt.Sprintf("%v %#v", log, log), string(auditKey))
+
+	// Продовження наявного журналу
+	log, err = signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	record, err := log.Append(signature.AuditRecord{KeyID: "apy_key", Operation: "CreateSignature"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), record.Seq)
+	assert.Nil(t, log.Close())
+	count, err = signature.VerifyAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+}
+
+// Test 2: Modification is detected
+func TestAuditLogModified(t *testing.T) {
+	path := writeAuditLog(t, 3)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	modified := bytes.Replace(content, []byte("strategy-1"), []byte("strategy-X"), 1)
+	assert.Nil(t, os.WriteFile(path, modified, 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+
+	// Без ключа ланцюжок не збігається
+	assert.Nil(t, os.WriteFile(path, content, 0o600))
+	_, err = signature.VerifyAuditLog(path, nil)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+
+	// Видалення запису з середини
+	lines := strings.SplitAfter(string(content), "\n")
+	assert.Nil(t, os.WriteFile(path, []byte(lines[0]+lines[2]), 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+	_, err = signature.OpenAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+}
+
+// Test 3: Truncation is detected
+func TestAuditLogTruncated(t *testing.T) {
+	path := writeAuditLog(t, 3)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.SplitAfter(string(content), "\n")
+
+	assert.Nil(t, os.WriteFile(path, []byte(lines[0]+lines[1]), 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTruncated)
+
+	assert.Nil(t, os.WriteFile(path, content[:len(content)-10], 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTruncated)
+
+	assert.Nil(t, os.Remove(path))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTruncated)
+}
+
+// Test 4: Head file is authenticated with the chain key
+func TestAuditLogHeadMAC(t *testing.T) {
+	path := writeAuditLog(t, 3)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.SplitAfter(string(content), "\n")
+
+	// Обрізаний журнал з head, переписаним під останній лишений запис
+	var record struct {
+		Seq  uint64 `json:"seq"`
+		Hash string `json:"hash"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Nil(t, os.WriteFile(path, []byte(lines[0]+lines[1]), 0o600))
+	head, err := json.Marshal(record)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(path+".head", head, 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+	_, err = signature.OpenAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+}
+
+// Test 5: Reopening recovers from a crash in the middle of a write
+func TestAuditLogRecovery(t *testing.T) {
+	path := writeAuditLog(t, 2)
+	head, err := os.ReadFile(path + ".head")
+	assert.Nil(t, err)
+
+	// Неповний рядок: збій до Sync, head ще вказує на другий запис
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.Nil(t, err)
+	_, err = file.WriteString(`{"seq":3,"time":"2021-01`)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTruncated)
+
+	log, err := signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	record, err := log.Append(signature.AuditRecord{KeyID: "apy_key", Operation: "CreateSignature"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), record.Seq)
+	assert.Nil(t, log.Close())
+	count, err := signature.VerifyAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	// Запис дописано, а head лишився попереднім
+	assert.Nil(t, os.WriteFile(path+".head", head, 0o600))
+	_, err = signature.VerifyAuditLog(path, auditKey)
+	assert.ErrorIs(t, err, signature.ErrAuditTampered)
+	log, err = signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	assert.Nil(t, log.Close())
+	count, err = signature.VerifyAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+}
+
+// Test 6: Algorithm names and secret redaction
+func TestAuditHelpers(t *testing.T) {
+	ed25519Sign, err := signature.NewSignEd25519("apy_key", rfc9421Ed25519PublicKey, rfc9421Ed25519PrivateKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "ed25519", signature.Algorithm(ed25519Sign))
+	assert.Equal(t, "ed25519ph", signature.Algorithm(ed25519Sign.WithOptions(signature.Ed25519phOptions(""))))
+	assert.Equal(t, "hmac-sha256", signature.Algorithm(signature.NewSignHMAC("apy_key", "apy_secret")))
+	assert.Equal(t, "*signature.SignPolicy", signature.Algorithm(&signature.SignPolicy{}))
+	assert.Equal(t, `api_secret=[REDACTED]&symbol=BTCUSDT {"password":"[REDACTED]"}`,
+		signature.RedactSecrets(`api_secret=s3cr3t&symbol=BTCUSDT {"password":"hunter2"}`))
+}
+
+// Test 7: Records hash exactly the signed string and keep the redacted signer error
+func TestSignAuditOutcome(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.log")
+	log, err := signature.OpenAuditLog(path, auditKey)
+	assert.Nil(t, err)
+	fake := signaturetest.NewFakeSigner("apy_key")
+	sign := signature.NewSignAudit(fake, log, "", "")
+
+	// Наявне поле підпису не входить ні до підписаного рядка, ні до хешу в журналі
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	params.Set("signature", "stale")
+	_, err = sign.SignParameters(params)
+	assert.Nil(t, err)
+	fake.SetError(errors.New("vault token=abc123 is sealed"))
+	_, err = sign.CreateSignatureContext(context.Background(), "timestamp=1610612740001")
+	assert.ErrorContains(t, err, "abc123")
+	_, err = sign.SignParameters(params)
+	assert.NotNil(t, err)
+	assert.Empty(t, sign.CreateSignature("timestamp=1610612740002"))
+	assert.Nil(t, log.Close())
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(content), "abc123")
+	var records []signature.AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record signature.AuditRecord
+		assert.Nil(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	if !assert.Len(t, records, 4) {
+		return
+	}
+	digest := sha256.Sum256([]byte("timestamp=1610612740000"))
+	assert.Equal(t, hex.EncodeToString(digest[:]), records[0].CanonicalSHA256)
+	assert.Equal(t, records[0].CanonicalSHA256, records[2].CanonicalSHA256)
+	assert.Empty(t, records[0].Error)
+	assert.Equal(t, "vault token=[REDACTED] is sealed", records[1].Error)
+	assert.Equal(t, "vault token=[REDACTED] is sealed", records[2].Error)
+	assert.Equal(t, signature.ErrEmptySignature.Error(), records[3].Error)
+}
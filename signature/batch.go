@@ -0,0 +1,53 @@
+package signature
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/bitly/go-simplejson"
+)
+
+var ErrNilParams = errors.New("params is nil")
+
+// Результат підпису одного набору параметрів у пакеті
+type BatchResult struct {
+	Params *simplejson.Json
+	Err    error
+}
+
+// Функція для паралельного підпису пакета наборів параметрів.
+// Порядок результатів відповідає порядку params, помилки повертаються для кожного елемента окремо.
+// parallelism - максимальна кількість горутин, якщо <= 0 - runtime.GOMAXPROCS(0).
+func SignParametersBatch(sign Sign, params []*simplejson.Json, parallelism int) []BatchResult {
+	results := make([]BatchResult, len(params))
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism > len(params) {
+		parallelism = len(params)
+	}
+
+	signer := JSON(sign)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(parallelism)
+	for worker := 0; worker < parallelism; worker++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if params[i] == nil {
+					results[i].Err = ErrNilParams
+					continue
+				}
+				results[i].Params, results[i].Err = signer.SignParameters(params[i])
+			}
+		}()
+	}
+	for i := range params {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,92 @@
+package signature_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+const batchSize = 256
+
+func batchParams(size int) []*simplejson.Json {
+	params := make([]*simplejson.Json, size)
+	for i := range params {
+		params[i] = simplejson.New()
+		params[i].Set("symbol", "BTCUSDT")
+		params[i].Set("side", "BUY")
+		params[i].Set("quantity", fmt.Sprintf("0.%03d", i))
+		params[i].Set("timestamp", 1610612740000+i)
+	}
+	return params
+}
+
+func batchSigners(b testing.TB) map[string]signature.SignJSON {
+	rsaSign, err := signature.NewSignRSA("apy_key", rsaTestPublicKey, rsaTestPrivateKey)
+	assert.Nil(b, err)
+	ed25519Sign, err := signature.NewSignEd25519("apy_key", rfc9421Ed25519PublicKey, rfc9421Ed25519PrivateKey)
+	assert.Nil(b, err)
+	return map[string]signature.SignJSON{
+		"HMAC":    signature.NewSignHMAC("apy_key", "apy_secret"),
+		"RSA":     rsaSign,
+		"Ed25519": ed25519Sign,
+	}
+}
+
+// Test 1: Batch signing preserves order and matches sequential signing
+func TestSignParametersBatch(t *testing.T) {
+	for name, sign := range batchSigners(t) {
+		params := batchParams(64)
+		params[10] = nil
+		results := signature.SignParametersBatch(sign, params, 4)
+		assert.Len(t, results, len(params), name)
+		for i, result := range results {
+			if i == 10 {
+				assert.ErrorIs(t, result.Err, signature.ErrNilParams)
+				continue
+			}
+			assert.Nil(t, result.Err)
+			expected, err := sign.SignParameters(params[i])
+			assert.Nil(t, err)
+			assert.Equal(t, expected.Get("signature").MustString(), result.Params.Get("signature").MustString(), name)
+			assert.True(t, sign.ValidateSignatureParams(result.Params), name)
+		}
+	}
+}
+
+// Test 2: Empty batch and default parallelism
+func TestSignParametersBatchEmpty(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	assert.Empty(t, signature.SignParametersBatch(sign, nil, 0))
+	assert.Len(t, signature.SignParametersBatch(sign, batchParams(3), 0), 3)
+}
+
+func BenchmarkSignParametersSequential(b *testing.B) {
+	for name, sign := range batchSigners(b) {
+		b.Run(name, func(b *testing.B) {
+			params := batchParams(batchSize)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				for _, p := range params {
+					if _, err := sign.SignParameters(p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSignParametersBatch(b *testing.B) {
+	for name, sign := range batchSigners(b) {
+		b.Run(name, func(b *testing.B) {
+			params := batchParams(batchSize)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				signature.SignParametersBatch(sign, params, 0)
+			}
+		})
+	}
+}
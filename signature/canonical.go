@@ -0,0 +1,155 @@
+package signature
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Канонізація параметрів без проміжних url.Values, fmt.Sprintf та копій JSON:
+// ключі сортуються у пульованому зрізі, значення форматуються strconv
+// і екрануються як url.QueryEscape прямо у вихідний буфер.
+// Результат збігається з url.Values{key: fmt.Sprintf("%v", value)}.Encode().
+
+const upperHex = "0123456789ABCDEF"
+
+var (
+	canonicalBuffers = sync.Pool{New: func() any {
+		buffer := make([]byte, 0, 512)
+		return &buffer
+	}}
+	canonicalKeys = sync.Pool{New: func() any {
+		keys := make([]string, 0, 16)
+		return &keys
+	}}
+)
+
+// Функція для дописування канонічного рядка параметрів у dst
+func AppendCanonical(dst []byte, params map[string]any) []byte {
+	return appendCanonical(dst, params, "")
+}
+
+// Функція для дописування канонічного рядка параметрів з уже відсортованими ключами.
+// Ключі, відсутні у params, пропускаються.
+func AppendCanonicalSorted(dst []byte, keys []string, params map[string]any) []byte {
+	first := true
+	for _, key := range keys {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+		if !first {
+			dst = append(dst, '&')
+		}
+		first = false
+		dst = appendQueryEscape(dst, key)
+		dst = append(dst, '=')
+		dst = appendCanonicalValue(dst, value)
+	}
+	return dst
+}
+
+// Функція для отримання канонічного рядка через пульований буфер,
+// єдина алокація - сам рядок результату
+func CanonicalString(params map[string]any) string {
+	return canonicalString(params, "")
+}
+
+func canonicalString(params map[string]any, skip string) string {
+	buffer := canonicalBuffers.Get().(*[]byte)
+	*buffer = appendCanonical((*buffer)[:0], params, skip)
+	result := string(*buffer)
+	canonicalBuffers.Put(buffer)
+	return result
+}
+
+// skip - ключ, який не входить до канонічного рядка (напр. "signature")
+func appendCanonical(dst []byte, params map[string]any, skip string) []byte {
+	keys := canonicalKeys.Get().(*[]string)
+	sorted := (*keys)[:0]
+	for key := range params {
+		if skip != "" && key == skip {
+			continue
+		}
+		sorted = append(sorted, key)
+	}
+	slices.Sort(sorted)
+	dst = AppendCanonicalSorted(dst, sorted, params)
+	clear(sorted)
+	*keys = sorted[:0]
+	canonicalKeys.Put(keys)
+	return dst
+}
+
+func appendCanonicalValue(dst []byte, value any) []byte {
+	switch value := value.(type) {
+	case string:
+		return appendQueryEscape(dst, value)
+	case json.Number:
+		return appendQueryEscape(dst, string(value))
+	}
+	// Числа форматуються у стековий буфер, потім екрануються ("+" у 1e+21)
+	var scratch [64]byte
+	return appendQueryEscapeBytes(dst, appendRawValue(scratch[:0], value))
+}
+
+// Форматування значення без екранування, як fmt.Sprintf("%v", value)
+func appendRawValue(dst []byte, value any) []byte {
+	switch value := value.(type) {
+	case string:
+		return append(dst, value...)
+	case json.Number:
+		return append(dst, value...)
+	case bool:
+		return strconv.AppendBool(dst, value)
+	case int:
+		return strconv.AppendInt(dst, int64(value), 10)
+	case int8:
+		return strconv.AppendInt(dst, int64(value), 10)
+	case int16:
+		return strconv.AppendInt(dst, int64(value), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(value), 10)
+	case int64:
+		return strconv.AppendInt(dst, value, 10)
+	case uint:
+		return strconv.AppendUint(dst, uint64(value), 10)
+	case uint8:
+		return strconv.AppendUint(dst, uint64(value), 10)
+	case uint16:
+		return strconv.AppendUint(dst, uint64(value), 10)
+	case uint32:
+		return strconv.AppendUint(dst, uint64(value), 10)
+	case uint64:
+		return strconv.AppendUint(dst, value, 10)
+	case float32:
+		return strconv.AppendFloat(dst, float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(dst, value, 'g', -1, 64)
+	case nil:
+		return append(dst, "<nil>"...)
+	}
+	return fmt.Appendf(dst, "%v", value)
+}
+
+func appendQueryEscape(dst []byte, s string) []byte {
+	return appendQueryEscapeBytes(dst, stringBytes(s))
+}
+
+// Екранування як у url.QueryEscape
+func appendQueryEscapeBytes(dst, s []byte) []byte {
+	for _, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			dst = append(dst, c)
+		case c == ' ':
+			dst = append(dst, '+')
+		default:
+			dst = append(dst, '%', upperHex[c>>4], upperHex[c&15])
+		}
+	}
+	return dst
+}
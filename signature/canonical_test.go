@@ -0,0 +1,107 @@
+package signature_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func orderParams() map[string]any {
+	return map[string]any{
+		"symbol":           "BTCUSDT",
+		"side":             "SELL",
+		"type":             "LIMIT",
+		"timeInForce":      "GTC",
+		"quantity":         0.01,
+		"price":            52000.0,
+		"newClientOrderId": "my_order_id_1",
+		"recvWindow":       5000,
+		"timestamp":        int64(1645423376532),
+		"apiKey":           "vmPUZE6mv9SD5VNHk4HlWFsOr6aKE2zvsw0MuIgwCIPy6utIco14y7Ju91duEh8A",
+	}
+}
+
+// Еталон - попередня реалізація ConvertSimpleJSONToString
+func referenceCanonical(params map[string]any) string {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, fmt.Sprintf("%v", value))
+	}
+	return values.Encode()
+}
+
+// Test 1: Canonical string matches url.Values encoding for all value kinds
+func TestCanonicalString(t *testing.T) {
+	params := orderParams()
+	params["escaped key&="] = "a b+c/d~é"
+	params["float"] = 1e21
+	params["small"] = 0.000001
+	params["float32"] = float32(0.1)
+	params["nan"] = math.NaN()
+	params["inf"] = math.Inf(1)
+	params["number"] = json.Number("1.50")
+	params["bool"] = true
+	params["nil"] = nil
+	params["uint8"] = uint8(200)
+	params["list"] = []any{"a", 1}
+	params["empty"] = ""
+	assert.Equal(t, referenceCanonical(params), signature.CanonicalString(params))
+	assert.Equal(t, "", signature.CanonicalString(nil))
+
+	keys := []string{"absent", "price", "symbol"}
+	assert.Equal(t, "price=52000&symbol=BTCUSDT", string(signature.AppendCanonicalSorted(nil, keys, params)))
+}
+
+// Test 2: Canonicalization of a 10-field request allocates nothing beyond the output
+func TestCanonicalZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	params := orderParams()
+	buffer := make([]byte, 0, 512)
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		buffer = signature.AppendCanonical(buffer[:0], params)
+	}))
+	assert.Equal(t, 1.0, testing.AllocsPerRun(100, func() {
+		signature.CanonicalString(params)
+	}))
+}
+
+// Test 3: Signed params are a copy, validation ignores the signature field
+func TestSignParametersCopy(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	params := simplejson.New()
+	for key, value := range orderParams() {
+		params.Set(key, value)
+	}
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.Nil(t, params.Get("signature").Interface())
+	assert.Equal(t, sign.CreateSignature(referenceCanonical(orderParams())), signed.Get("signature").MustString())
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	signed.Set("price", 1.0)
+	assert.False(t, sign.ValidateSignatureParams(signed))
+}
+
+func BenchmarkCanonicalReference(b *testing.B) {
+	params := orderParams()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		referenceCanonical(params)
+	}
+}
+
+func BenchmarkAppendCanonical(b *testing.B) {
+	params := orderParams()
+	buffer := make([]byte, 0, 512)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		buffer = signature.AppendCanonical(buffer[:0], params)
+	}
+}
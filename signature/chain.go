@@ -0,0 +1,146 @@
+package signature
+
+import "context"
+
+// Ланцюжок проміжних обробників навколо Sign. Кожен Middleware може обгорнути
+// три етапи: канонізацію параметрів, створення підпису та перевірку підпису.
+// Код обробника до виклику next виконується перед етапом, після - над його результатом,
+// обробник може й не викликати next (кеш, відмова політики).
+// Chain реалізує Sign, SignContext та SignJSON через ці етапи:
+//
+//	CreateSignature, CreateSignatureContext - Sign
+//	SignParameters                          - Canonicalize, Sign
+//	ValidateSignatureParams                 - Canonicalize, Verify
+//	ValidateSignature                       - Verify
+//
+// ctx з CreateSignatureContext передається етапу Sign і далі обгорнутому Sign,
+// інші методи передають context.Background().
+// Перший Middleware - зовнішній, тобто бачить виклик першим, а результат - останнім.
+
+type (
+	// skip - ключ поля підпису, що не входить до канонічного рядка
+	CanonicalizeFunc func(params map[string]any, skip string) (string, error)
+	SignFunc         func(ctx context.Context, message string) (string, error)
+	VerifyFunc       func(message, signature string) bool
+	Middleware       struct {
+		Canonicalize func(next CanonicalizeFunc) CanonicalizeFunc // nil - без обгортки
+		Sign         func(next SignFunc) SignFunc
+		Verify       func(next VerifyFunc) VerifyFunc
+	}
+	// Хуки до та після етапів, для обробників, яким не потрібно керувати викликом next.
+	// Помилка Before* перериває етап, After* лише спостерігають результат.
+	Hooks struct {
+		BeforeCanonicalize func(params map[string]any) error
+		AfterCanonicalize  func(canonical string, err error)
+		BeforeSign         func(message string) error
+		AfterSign          func(message, signature string, err error)
+		BeforeVerify       func(message, signature string) error
+		AfterVerify        func(message, signature string, valid bool)
+	}
+	SignChain struct {
+		sign         Sign
+		canonicalize CanonicalizeFunc
+		create       SignFunc
+		verify       VerifyFunc
+	}
+)
+
+// Функція для побудови ланцюжка навколо sign
+func Chain(sign Sign, middleware ...Middleware) *SignChain {
+	chain := &SignChain{
+		sign: sign,
+		canonicalize: func(params map[string]any, skip string) (string, error) {
+			return canonicalString(params, skip), nil
+		},
+		// Причина невдачі (відмова політики, очікування погодження, транспорт) - через SignContext
+		create: func(ctx context.Context, message string) (string, error) {
+			return createSignature(ctx, sign, message)
+		},
+		verify: sign.ValidateSignature,
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i].Canonicalize != nil {
+			chain.canonicalize = middleware[i].Canonicalize(chain.canonicalize)
+		}
+		if middleware[i].Sign != nil {
+			chain.create = middleware[i].Sign(chain.create)
+		}
+		if middleware[i].Verify != nil {
+			chain.verify = middleware[i].Verify(chain.verify)
+		}
+	}
+	return chain
+}
+
+// Функція для перетворення хуків на Middleware, незадані хуки пропускаються
+func (hooks Hooks) Middleware() Middleware {
+	var middleware Middleware
+	if hooks.BeforeCanonicalize != nil || hooks.AfterCanonicalize != nil {
+		middleware.Canonicalize = func(next CanonicalizeFunc) CanonicalizeFunc {
+			return func(params map[string]any, skip string) (canonical string, err error) {
+				if hooks.BeforeCanonicalize != nil {
+					err = hooks.BeforeCanonicalize(params)
+				}
+				if err == nil {
+					canonical, err = next(params, skip)
+				}
+				if hooks.AfterCanonicalize != nil {
+					hooks.AfterCanonicalize(canonical, err)
+				}
+				return
+			}
+		}
+	}
+	if hooks.BeforeSign != nil || hooks.AfterSign != nil {
+		middleware.Sign = func(next SignFunc) SignFunc {
+			return func(ctx context.Context, message string) (signature string, err error) {
+				if hooks.BeforeSign != nil {
+					err = hooks.BeforeSign(message)
+				}
+				if err == nil {
+					signature, err = next(ctx, message)
+				}
+				if hooks.AfterSign != nil {
+					hooks.AfterSign(message, signature, err)
+				}
+				return
+			}
+		}
+	}
+	if hooks.BeforeVerify != nil || hooks.AfterVerify != nil {
+		middleware.Verify = func(next VerifyFunc) VerifyFunc {
+			return func(message, signature string) (valid bool) {
+				if hooks.BeforeVerify == nil || hooks.BeforeVerify(message, signature) == nil {
+					valid = next(message, signature)
+				}
+				if hooks.AfterVerify != nil {
+					hooks.AfterVerify(message, signature, valid)
+				}
+				return
+			}
+		}
+	}
+	return middleware
+}
+
+// Помилка етапу підпису повертається порожнім рядком, як у вбудованих підписувачів
+func (chain *SignChain) CreateSignature(queryString string) string {
+	signature, err := chain.create(context.Background(), queryString)
+	if err != nil {
+		return ""
+	}
+	return signature
+}
+
+// Функція для створення підпису з помилкою етапу підпису, ctx передається етапам Sign
+func (chain *SignChain) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	return chain.create(ctx, queryString)
+}
+
+func (chain *SignChain) ValidateSignature(message, signature string) bool {
+	return chain.verify(message, signature)
+}
+
+func (chain *SignChain) GetAPIKey() string {
+	return chain.sign.GetAPIKey()
+}
@@ -0,0 +1,233 @@
+package signature_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Middleware, що записує порядок входу та виходу з етапів
+func tracingMiddleware(name string, trace *[]string) signature.Middleware {
+	return signature.Middleware{
+		Canonicalize: func(next signature.CanonicalizeFunc) signature.CanonicalizeFunc {
+			return func(params map[string]any, skip string) (string, error) {
+				*trace = append(*trace, name+" canonicalize")
+				canonical, err := next(params, skip)
+				*trace = append(*trace, name+" canonicalized")
+				return canonical, err
+			}
+		},
+		Sign: func(next signature.SignFunc) signature.SignFunc {
+			return func(ctx context.Context, message string) (string, error) {
+				*trace = append(*trace, name+" sign")
+				signature, err := next(ctx, message)
+				*trace = append(*trace, name+" signed")
+				return signature, err
+			}
+		},
+		Verify: func(next signature.VerifyFunc) signature.VerifyFunc {
+			return func(message, signature string) bool {
+				*trace = append(*trace, name+" verify")
+				valid := next(message, signature)
+				*trace = append(*trace, name+" verified")
+				return valid
+			}
+		},
+	}
+}
+
+// Test 1: Chain without middleware behaves like the wrapped signer
+func TestChainRoundTrip(t *testing.T) {
+	inner := signature.NewSignHMAC("apy_key", "apy_secret")
+	sign := signature.Chain(inner)
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signed.Get("signature").MustString())
+	assert.Nil(t, params.Get("signature").Interface())
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.True(t, inner.ValidateSignatureParams(signed))
+	assert.Equal(t, inner.CreateSignature("timestamp=1610612740000"), sign.CreateSignature("timestamp=1610612740000"))
+	assert.Equal(t, "apy_key", sign.GetAPIKey())
+
+	signed.Set("timestamp", 1)
+	assert.False(t, sign.ValidateSignatureParams(signed))
+	assert.False(t, sign.ValidateSignatureParams(params))
+	assert.False(t, sign.ValidateSignatureParams(nil))
+	_, err = sign.SignParameters(nil)
+	assert.ErrorIs(t, err, signature.ErrNilParams)
+}
+
+// Test 2: The first middleware is the outermost
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	sign := signature.Chain(signature.NewSignHMAC("apy_key", "apy_secret"),
+		tracingMiddleware("a", &trace), tracingMiddleware("b", &trace))
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"a canonicalize", "b canonicalize", "b canonicalized", "a canonicalized",
+		"a sign", "b sign", "b signed", "a signed",
+	}, trace)
+
+	trace = nil
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.Equal(t, []string{
+		"a canonicalize", "b canonicalize", "b canonicalized", "a canonicalized",
+		"a verify", "b verify", "b verified", "a verified",
+	}, trace)
+}
+
+// Test 3: Middleware can short-circuit signing (cache)
+func TestChainCache(t *testing.T) {
+	var mutex sync.Mutex
+	cache := make(map[string]string)
+	calls := 0
+	counting := signature.Middleware{
+		Sign: func(next signature.SignFunc) signature.SignFunc {
+			return func(ctx context.Context, message string) (string, error) {
+				calls++
+				return next(ctx, message)
+			}
+		},
+	}
+	caching := signature.Middleware{
+		Sign: func(next signature.SignFunc) signature.SignFunc {
+			return func(ctx context.Context, message string) (string, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if cached, ok := cache[message]; ok {
+					return cached, nil
+				}
+				signature, err := next(ctx, message)
+				if err == nil {
+					cache[message] = signature
+				}
+				return signature, err
+			}
+		},
+	}
+	sign := signature.Chain(signature.NewSignHMAC("apy_key", "apy_secret"), caching, counting)
+	first := sign.CreateSignature("timestamp=1610612740000")
+	second := sign.CreateSignature("timestamp=1610612740000")
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, calls)
+}
+
+// Test 4: Hooks run before and after stages, Before* errors abort
+func TestChainHooks(t *testing.T) {
+	denied := errors.New("denied")
+	var canonicals, signatures []string
+	var verified []bool
+	sign := signature.Chain(signature.NewSignHMAC("apy_key", "apy_secret"), signature.Hooks{
+		BeforeCanonicalize: func(params map[string]any) error {
+			if params["symbol"] == "DOGEUSDT" {
+				return denied
+			}
+			return nil
+		},
+		AfterCanonicalize: func(canonical string, err error) { canonicals = append(canonicals, canonical) },
+		BeforeSign: func(message string) error {
+			if message == "blocked" {
+				return denied
+			}
+			return nil
+		},
+		AfterSign:    func(message, signature string, err error) { signatures = append(signatures, signature) },
+		BeforeVerify: func(message, signature string) error { return nil },
+		AfterVerify:  func(message, signature string, valid bool) { verified = append(verified, valid) },
+	}.Middleware())
+
+	params := simplejson.New()
+	params.Set("symbol", "BTCUSDT")
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.Equal(t, []string{"symbol=BTCUSDT&timestamp=1610612740000", "symbol=BTCUSDT&timestamp=1610612740000"}, canonicals)
+	assert.Equal(t, []string{signed.Get("signature").MustString()}, signatures)
+	assert.Equal(t, []bool{true}, verified)
+
+	params.Set("symbol", "DOGEUSDT")
+	_, err = sign.SignParameters(params)
+	assert.ErrorIs(t, err, denied)
+	assert.Empty(t, sign.CreateSignature("blocked"))
+}
+
+// Test 5: Failed signing is reported as an error
+func TestChainEmptySignature(t *testing.T) {
+	inner := signature.NewSignHMAC("apy_key", "apy_secret")
+	inner.Destroy()
+	sign := signature.Chain(inner)
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	_, err := sign.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+	// Підписувач без SignContext повідомляє про невдачу лише порожнім підписом
+	sign = signature.Chain(struct{ signature.Sign }{inner})
+	_, err = sign.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrEmptySignature)
+}
+
+// Test 6: Errors of the wrapped signer reach SignParameters unchanged
+func TestChainSignerErrors(t *testing.T) {
+	signPolicy, _ := testSignPolicy(t)
+	params := simplejson.New()
+	params.Set("symbol", "BTCUSDT")
+	params.Set("timestamp", 1610612740000)
+	_, err := signature.Chain(signPolicy).SignParameters(params)
+	var policyErr *signature.PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+	// Політика обмежує ендпоінти, тож підпис без ендпоінта відхиляється
+	assert.ErrorIs(t, err, signature.ErrPolicyEndpoint)
+
+	queue, _, _ := testApprovalQueue(t, 2)
+	params = simplejson.New()
+	params.Set("address", "bc1qtrusted")
+	params.Set("timestamp", 1610612740000)
+	_, err = signature.Chain(queue).SignParameters(params)
+	var pending *signature.ApprovalPendingError
+	assert.ErrorAs(t, err, &pending)
+	request, err := queue.Request(pending.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "address=bc1qtrusted&timestamp=1610612740000", request.Canonical)
+
+	transport := errors.New("connection refused")
+	fake := signaturetest.NewFakeSigner("apy_key")
+	fake.SetError(transport)
+	_, err = signature.Chain(fake).SignParameters(params)
+	assert.ErrorIs(t, err, transport)
+	assert.Empty(t, signature.Chain(fake).CreateSignature("timestamp=1610612740000"))
+}
+
+// Test 7: CreateSignatureContext passes ctx through middleware to the wrapped signer
+func TestChainContext(t *testing.T) {
+	var seen []any
+	observing := signature.Middleware{
+		Sign: func(next signature.SignFunc) signature.SignFunc {
+			return func(ctx context.Context, message string) (string, error) {
+				seen = append(seen, ctx.Value(traceKey{}))
+				return next(ctx, message)
+			}
+		},
+	}
+	inner := &traceSigner{SignHMAC: signature.NewSignHMAC("apy_key", "apy_secret")}
+	sign := signature.Chain(inner, observing)
+	signed, err := sign.CreateSignatureContext(context.WithValue(context.Background(), traceKey{}, "request"), "timestamp=1610612740000")
+	assert.Nil(t, err)
+	assert.Equal(t, signature.NewSignHMAC("apy_key", "apy_secret").CreateSignature("timestamp=1610612740000"), signed)
+	// ctx дійшов і до middleware, і до обгорнутого Sign
+	assert.Equal(t, []any{"request"}, seen)
+	assert.Equal(t, "request", inner.span)
+}
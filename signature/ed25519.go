@@ -1,13 +1,12 @@
 package signature
 
 import (
+	"context"
 	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/base64"
-	"encoding/pem"
 	"errors"
-
-	"github.com/bitly/go-simplejson"
+	"sync"
 )
 
 // How can I use Ed25519 API keys?
@@ -18,43 +17,34 @@ import (
 
 // openssl pkey -pubout -in test-prv-key.pem -out test-pub-key.pem
 
-type SignEd25519 struct {
-	apiKey     string
-	privateKey ed25519.PrivateKey
-	publicKey  ed25519.PublicKey
-}
+type (
+	SignEd25519 struct {
+		apiKey    string
+		private   *ed25519PrivateKey // спільний з підписувачами з WithOptions
+		publicKey ed25519.PublicKey
+		// Варіант Ed25519ph чи Ed25519ctx, nil - чистий Ed25519
+		options *ed25519.Options
+	}
+	// Приватний ключ під RWMutex: підписи читають ключ паралельно, Destroy затирає його ексклюзивно
+	ed25519PrivateKey struct {
+		mutex sync.RWMutex
+		key   ed25519.PrivateKey
+	}
+)
 
 // Функція для створення підпису Ed25519
 func (sign *SignEd25519) CreateSignature(queryString string) string {
-	signature := ed25519.Sign(sign.privateKey, []byte(queryString))
-	return base64.StdEncoding.EncodeToString(signature)
-}
-
-func (sign *SignEd25519) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
-	return signParameters(params, sign)
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
 }
 
-// Функція для валідації підпису
-func (sign *SignEd25519) ValidateSignatureParams(params *simplejson.Json) bool {
-	// Витягування підпису з параметрів
-	signature, err := params.Get("signature").String()
+// Функція для створення підпису з помилкою, після Destroy - ErrSignerDestroyed
+func (sign *SignEd25519) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	signature, err := sign.signWithOptions([]byte(queryString), sign.options)
 	if err != nil {
-		return false
+		return "", err
 	}
-
-	// Видалення підпису з параметрів
-	js, _ := params.MarshalJSON()
-	unsignedParams, _ := simplejson.NewJson(js)
-	unsignedParams.Del("signature")
-
-	// Отримання строки параметрів
-	message, err := ConvertSimpleJSONToString(unsignedParams)
-	if err != nil {
-		return false
-	}
-
-	// Валідація підпису
-	return sign.ValidateSignature(message, signature)
+	return base64.StdEncoding.EncodeToString(signature), nil
 }
 
 func (sign *SignEd25519) ValidateSignature(message, signature string) bool {
@@ -65,13 +55,30 @@ func (sign *SignEd25519) ValidateSignature(message, signature string) bool {
 	}
 
 	// Валідація підпису
-	return ed25519.Verify(sign.publicKey, []byte(message), signatureBytes)
+	return sign.verifyWithOptions([]byte(message), signatureBytes, sign.options)
 }
 
 func (sign *SignEd25519) GetAPIKey() string {
 	return sign.apiKey
 }
 
+// Функція для затирання приватного ключа, після неї підписувач (і підписувачі
+// з WithOptions) лише перевіряє підписи
+func (sign *SignEd25519) Destroy() {
+	sign.private.mutex.Lock()
+	defer sign.private.mutex.Unlock()
+	munlock(sign.private.key)
+	wipeBytes(sign.private.key)
+	sign.private.key = nil
+}
+
+// Функція для закріплення приватного ключа в оперативній пам'яті (mlock)
+func (sign *SignEd25519) Mlock() error {
+	sign.private.mutex.RLock()
+	defer sign.private.mutex.RUnlock()
+	return mlock(sign.private.key)
+}
+
 func NewSignEd25519(apiKey string, publicKey string, privateKey string) (signer *SignEd25519, err error) {
 	private, err := loadEd25519PrivateKeyFromPEM(privateKey)
 	if err != nil {
@@ -82,18 +89,18 @@ func NewSignEd25519(apiKey string, publicKey string, privateKey string) (signer
 		return
 	}
 	signer = &SignEd25519{
-		apiKey:     apiKey,
-		privateKey: ed25519.PrivateKey(private),
-		publicKey:  ed25519.PublicKey(public),
+		apiKey:    apiKey,
+		private:   &ed25519PrivateKey{key: ed25519.PrivateKey(private)},
+		publicKey: ed25519.PublicKey(public),
 	}
 	return
 }
 
 // Функція для завантаження приватного ключа з PEM рядка
 func loadEd25519PrivateKeyFromPEM(content string) (ed25519.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(content))
-	if block == nil || block.Type != "PRIVATE KEY" {
-		return nil, errors.New("failed to decode PEM block containing private key")
+	block, err := decodePEMBlock(content, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
 	}
 
 	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
@@ -111,9 +118,9 @@ func loadEd25519PrivateKeyFromPEM(content string) (ed25519.PrivateKey, error) {
 
 // Функція для завантаження публічного ключа з PEM рядка
 func loadEd25519PublicKeyFromPEM(content string) (ed25519.PublicKey, error) {
-	block, _ := pem.Decode([]byte(content))
-	if block == nil || block.Type != "PUBLIC KEY" {
-		return nil, errors.New("failed to decode PEM block containing public key")
+	block, err := decodePEMBlock(content, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
 	}
 
 	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
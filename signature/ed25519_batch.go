@@ -0,0 +1,177 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+
+	"filippo.io/edwards25519"
+)
+
+// Пакетна перевірка підписів Ed25519 для шлюзів з великим потоком запитів.
+// Усі трійки (publicKey, message, signature) перевіряються одним рівнянням
+// з випадковими 128-бітними коефіцієнтами z_i:
+//
+//	[-Σ z_i·S_i]B + Σ [z_i]R_i + Σ [z_i·k_i]A_i = 0
+//
+// Рівняння без кофактора, як у ed25519.Verify. Щоб компоненти кручення не
+// скорочувались у сумі, пакет відхиляється, якщо A_i чи R_i малого порядку
+// або A_i має компонент кручення (перевіряється один раз на ключ). Хибне
+// відхилення пакета безпечне: Verify тоді перевіряє підписи поодинці
+// через ed25519.Verify. Перевірка R_i змішаного порядку коштувала б скалярного
+// множення на підпис, тому такий R_i (його може створити лише власник ключа)
+// виявляється рівнянням з імовірністю не менше 1/2, а не завжди.
+
+type (
+	Ed25519BatchVerifier struct {
+		entries []ed25519BatchEntry
+		// Публічні ключі, перевірені на компонент кручення: ключ -> без кручення
+		publicKeys map[string]bool
+		// Джерело випадкових коефіцієнтів, за замовчуванням crypto/rand
+		Rand io.Reader
+	}
+	ed25519BatchEntry struct {
+		publicKey ed25519.PublicKey
+		message   []byte
+		signature []byte
+	}
+)
+
+// Максимальна кількість перевірених ключів, після неї кеш очищається
+const maxEd25519BatchKeys = 1024
+
+// L - 1, де L - порядок базової точки
+var ed25519OrderMinusOne, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{
+	0xec, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+})
+
+func NewEd25519BatchVerifier() *Ed25519BatchVerifier {
+	return &Ed25519BatchVerifier{Rand: rand.Reader}
+}
+
+// Функція для додавання трійки до пакета, зрізи не копіюються
+func (verifier *Ed25519BatchVerifier) Add(publicKey ed25519.PublicKey, message, signature []byte) {
+	verifier.entries = append(verifier.entries, ed25519BatchEntry{publicKey: publicKey, message: message, signature: signature})
+}
+
+// Функція для додавання підпису у форматі SignEd25519.CreateSignature (Base64)
+func (verifier *Ed25519BatchVerifier) AddSignature(sign *SignEd25519, message, signature string) {
+	// Некоректний Base64 лишає порожній підпис, такий запис не пройде перевірку
+	signatureBytes, _ := base64.StdEncoding.DecodeString(signature)
+	verifier.Add(sign.publicKey, []byte(message), signatureBytes)
+}
+
+func (verifier *Ed25519BatchVerifier) Len() int {
+	return len(verifier.entries)
+}
+
+// Функція для очищення пакета зі збереженням виділеної пам'яті
+func (verifier *Ed25519BatchVerifier) Reset() {
+	clear(verifier.entries)
+	verifier.entries = verifier.entries[:0]
+}
+
+// Функція для перевірки пакета, результати - у порядку додавання.
+// valid - true, якщо всі підписи коректні.
+func (verifier *Ed25519BatchVerifier) Verify() (valid bool, results []bool) {
+	results = make([]bool, len(verifier.entries))
+	if verifier.VerifyBatch() {
+		for i := range results {
+			results[i] = true
+		}
+		return true, results
+	}
+	valid = true
+	for i, entry := range verifier.entries {
+		results[i] = len(entry.publicKey) == ed25519.PublicKeySize &&
+			ed25519.Verify(entry.publicKey, entry.message, entry.signature)
+		valid = valid && results[i]
+	}
+	return
+}
+
+// Функція для перевірки пакета лише рівнянням, без пошуку хибних підписів
+func (verifier *Ed25519BatchVerifier) VerifyBatch() bool {
+	count := len(verifier.entries)
+	if count == 0 {
+		return true
+	}
+	random := verifier.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+	coefficients := make([]byte, 16*count)
+	if _, err := io.ReadFull(random, coefficients); err != nil {
+		return false
+	}
+
+	// scalars/points: B, далі R_i та A_i
+	scalars := make([]*edwards25519.Scalar, 1, 2*count+1)
+	points := make([]*edwards25519.Point, 1, 2*count+1)
+	sum := edwards25519.NewScalar()
+	var (
+		wide   [64]byte
+		digest [sha512.Size]byte
+	)
+	for i, entry := range verifier.entries {
+		if len(entry.publicKey) != ed25519.PublicKeySize || len(entry.signature) != ed25519.SignatureSize {
+			return false
+		}
+		publicKey, err := new(edwards25519.Point).SetBytes(entry.publicKey)
+		if err != nil || !verifier.primeOrderKey(entry.publicKey, publicKey) {
+			return false
+		}
+		r, err := new(edwards25519.Point).SetBytes(entry.signature[:32])
+		// ed25519.Verify порівнює закодоване R побайтово, неканонічне R відкидається
+		if err != nil || string(r.Bytes()) != string(entry.signature[:32]) || ed25519SmallOrder(r) {
+			return false
+		}
+		s, err := edwards25519.NewScalar().SetCanonicalBytes(entry.signature[32:])
+		if err != nil {
+			return false
+		}
+
+		copy(wide[:16], coefficients[16*i:16*(i+1)])
+		z, _ := edwards25519.NewScalar().SetUniformBytes(wide[:])
+
+		// k = SHA-512(R || A || M) mod L
+		hash := sha512.New()
+		hash.Write(entry.signature[:32])
+		hash.Write(entry.publicKey)
+		hash.Write(entry.message)
+		k, _ := edwards25519.NewScalar().SetUniformBytes(hash.Sum(digest[:0]))
+
+		sum.MultiplyAdd(z, s, sum)
+		scalars = append(scalars, z, k.Multiply(z, k))
+		points = append(points, r, publicKey)
+	}
+	scalars[0] = sum.Negate(sum)
+	points[0] = edwards25519.NewGeneratorPoint()
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// Ключ не малого порядку і без компонента кручення, результат кешується
+func (verifier *Ed25519BatchVerifier) primeOrderKey(encoded []byte, publicKey *edwards25519.Point) bool {
+	if valid, ok := verifier.publicKeys[string(encoded)]; ok {
+		return valid
+	}
+	// Перевірка [L]A = 0 дорога (скалярне множення), тому один раз на ключ
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(
+		[]*edwards25519.Scalar{ed25519OrderMinusOne}, []*edwards25519.Point{publicKey})
+	valid := !ed25519SmallOrder(publicKey) && check.Add(check, publicKey).Equal(edwards25519.NewIdentityPoint()) == 1
+	if verifier.publicKeys == nil || len(verifier.publicKeys) >= maxEd25519BatchKeys {
+		verifier.publicKeys = make(map[string]bool)
+	}
+	verifier.publicKeys[string(encoded)] = valid
+	return valid
+}
+
+// Точка малого порядку: [8]P = 0
+func ed25519SmallOrder(point *edwards25519.Point) bool {
+	return new(edwards25519.Point).MultByCofactor(point).Equal(edwards25519.NewIdentityPoint()) == 1
+}
@@ -0,0 +1,167 @@
+package signature_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"strconv"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type ed25519Triple struct {
+	publicKey ed25519.PublicKey
+	message   []byte
+	signature []byte
+}
+
+func ed25519Triples(count int) []ed25519Triple {
+	triples := make([]ed25519Triple, count)
+	for i := range triples {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0], seed[1] = byte(i), byte(i>>8)
+		privateKey := ed25519.NewKeyFromSeed(seed)
+		message := []byte("symbol=BTCUSDT&timestamp=" + strconv.Itoa(1610612740000+i))
+		triples[i] = ed25519Triple{
+			publicKey: privateKey.Public().(ed25519.PublicKey),
+			message:   message,
+			signature: ed25519.Sign(privateKey, message),
+		}
+	}
+	return triples
+}
+
+func ed25519Batch(triples []ed25519Triple) *signature.Ed25519BatchVerifier {
+	verifier := signature.NewEd25519BatchVerifier()
+	for _, triple := range triples {
+		verifier.Add(triple.publicKey, triple.message, triple.signature)
+	}
+	return verifier
+}
+
+// Test 1: Valid batch
+func TestEd25519BatchVerify(t *testing.T) {
+	verifier := ed25519Batch(ed25519Triples(64))
+	assert.Equal(t, 64, verifier.Len())
+	assert.True(t, verifier.VerifyBatch())
+	valid, results := verifier.Verify()
+	assert.True(t, valid)
+	assert.Len(t, results, 64)
+	assert.NotContains(t, results, false)
+
+	verifier.Reset()
+	assert.Zero(t, verifier.Len())
+	valid, results = verifier.Verify()
+	assert.True(t, valid)
+	assert.Empty(t, results)
+}
+
+// Test 2: Invalid items are pinpointed by per-item fallback
+func TestEd25519BatchVerifyInvalid(t *testing.T) {
+	triples := ed25519Triples(16)
+	// Чуже повідомлення
+	triples[3].message = []byte("symbol=ETHUSDT")
+	// Зіпсований підпис
+	triples[7].signature = append([]byte(nil), triples[7].signature...)
+	triples[7].signature[40] ^= 1
+	// Неправильна довжина ключа
+	triples[11].publicKey = triples[11].publicKey[:16]
+	verifier := ed25519Batch(triples)
+	assert.False(t, verifier.VerifyBatch())
+	valid, results := verifier.Verify()
+	assert.False(t, valid)
+	for i, result := range results {
+		assert.Equal(t, i != 3 && i != 7 && i != 11, result, i)
+	}
+}
+
+// Test 3: Batch of SignEd25519 signatures
+func TestEd25519BatchAddSignature(t *testing.T) {
+	sign, err := signature.NewSignEd25519("apy_key", rfc9421Ed25519PublicKey, rfc9421Ed25519PrivateKey)
+	assert.Nil(t, err)
+	verifier := signature.NewEd25519BatchVerifier()
+	for i := 0; i < 8; i++ {
+		message := "timestamp=" + strconv.Itoa(1610612740000+i)
+		verifier.AddSignature(sign, message, sign.CreateSignature(message))
+	}
+	verifier.AddSignature(sign, "timestamp=1610612740000", "not base64")
+	valid, results := verifier.Verify()
+	assert.False(t, valid)
+	assert.Equal(t, []bool{true, true, true, true, true, true, true, true, false}, results)
+}
+
+// Test 4: Torsion components are rejected, batch agrees with ed25519.Verify
+func TestEd25519BatchVerifyTorsion(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	digest := sha512.Sum512(seed)
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(digest[:32])
+	assert.Nil(t, err)
+	publicKey := new(edwards25519.Point).ScalarBaseMult(secret)
+	// Точка порядку 2: y = -1 = p - 1
+	torsion, err := new(edwards25519.Point).SetBytes(append([]byte{0xec}, append(bytes.Repeat([]byte{0xff}, 30), 0x7f)...))
+	assert.Nil(t, err)
+
+	// Підпис з R = T: рівняння з кофактором його приймає, ed25519.Verify - ні
+	signWith := func(r *edwards25519.Scalar, rPoint, aPoint *edwards25519.Point, message []byte) []byte {
+		hash := sha512.New()
+		hash.Write(rPoint.Bytes())
+		hash.Write(aPoint.Bytes())
+		hash.Write(message)
+		k, _ := edwards25519.NewScalar().SetUniformBytes(hash.Sum(nil))
+		s := edwards25519.NewScalar().MultiplyAdd(k, secret, r)
+		return append(rPoint.Bytes(), s.Bytes()...)
+	}
+	triples := ed25519Triples(4)
+	message := []byte("timestamp=1610612740000")
+	triples = append(triples, ed25519Triple{
+		publicKey: publicKey.Bytes(),
+		message:   message,
+		signature: signWith(edwards25519.NewScalar(), torsion, publicKey, message),
+	})
+
+	// Ключ A + T: R = [r]B, підпис відхиляється ed25519.Verify, коли k непарне
+	mixedKey := new(edwards25519.Point).Add(publicKey, torsion)
+	r, _ := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte{7}, 64))
+	rPoint := new(edwards25519.Point).ScalarBaseMult(r)
+	for i := 0; ; i++ {
+		message := []byte("timestamp=" + strconv.Itoa(1610612740000+i))
+		signed := signWith(r, rPoint, mixedKey, message)
+		if !ed25519.Verify(mixedKey.Bytes(), message, signed) {
+			triples = append(triples, ed25519Triple{publicKey: mixedKey.Bytes(), message: message, signature: signed})
+			break
+		}
+	}
+
+	for _, bad := range []int{4, 5} {
+		verifier := ed25519Batch(append(triples[:4:4], triples[bad]))
+		assert.False(t, verifier.VerifyBatch(), bad)
+		valid, results := verifier.Verify()
+		assert.False(t, valid)
+		assert.Equal(t, []bool{true, true, true, true, false}, results)
+	}
+}
+
+func BenchmarkEd25519VerifySequential(b *testing.B) {
+	triples := ed25519Triples(64)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, triple := range triples {
+			if !ed25519.Verify(triple.publicKey, triple.message, triple.signature) {
+				b.Fatal("invalid signature")
+			}
+		}
+	}
+}
+
+func BenchmarkEd25519VerifyBatch(b *testing.B) {
+	verifier := ed25519Batch(ed25519Triples(64))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if !verifier.VerifyBatch() {
+			b.Fatal("invalid batch")
+		}
+	}
+}
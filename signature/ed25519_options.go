@@ -0,0 +1,110 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Варіанти Ed25519 з RFC 8032 через ed25519.Options:
+//
+//	Ed25519ph  - Options{Hash: crypto.SHA512}, повідомлення попередньо хешується SHA-512
+//	             (для великих тіл запитів), Context - необов'язковий
+//	Ed25519ctx - Options{Context: "..."}, доменне розділення підписів між сервісами;
+//	             порожній Context (RFC 8032, розділ 8.3) відхиляється помилкою ErrEd25519Context,
+//	             бо crypto/ed25519 мовчки підписав би ним чистий Ed25519
+//
+// Методи приймають саме повідомлення, хешування для Ed25519ph виконується тут.
+
+var (
+	ErrEd25519PrivateKey = errors.New("ed25519 private key is not available")
+	ErrEd25519Context    = errors.New("ed25519ctx context must not be empty")
+)
+
+// Після Destroy ключ недоступний саме через знищення підписувача
+var errEd25519Destroyed = fmt.Errorf("%w: %w", ErrEd25519PrivateKey, ErrSignerDestroyed)
+
+func Ed25519phOptions(context string) ed25519.Options {
+	return ed25519.Options{Hash: crypto.SHA512, Context: context}
+}
+
+func Ed25519ctxOptions(context string) ed25519.Options {
+	return ed25519.Options{Context: context}
+}
+
+// Функція для створення підписувача з тими самими ключами, що підписує
+// та перевіряє CreateSignature/ValidateSignature у вказаному варіанті.
+// Ключі спільні, Destroy одного з підписувачів затирає ключ обох, після чого
+// обидва повертають ErrSignerDestroyed.
+func (sign *SignEd25519) WithOptions(options ed25519.Options) *SignEd25519 {
+	clone := *sign
+	clone.options = &options
+	return &clone
+}
+
+// Функція для створення підпису у вказаному варіанті, результат у Base64
+func (sign *SignEd25519) CreateSignatureWithOptions(message string, options ed25519.Options) (string, error) {
+	signature, err := sign.signWithOptions([]byte(message), &options)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Функція для валідації підпису у вказаному варіанті
+func (sign *SignEd25519) ValidateSignatureWithOptions(message, signature string, options ed25519.Options) bool {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return sign.verifyWithOptions([]byte(message), signatureBytes, &options)
+}
+
+// Підпис під блокуванням читання, щоб Destroy не затер ключ посеред підпису
+func (sign *SignEd25519) signWithOptions(message []byte, options *ed25519.Options) ([]byte, error) {
+	sign.private.mutex.RLock()
+	defer sign.private.mutex.RUnlock()
+	privateKey := sign.private.key
+	if privateKey == nil {
+		return nil, errEd25519Destroyed
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrEd25519PrivateKey
+	}
+	if options == nil {
+		return ed25519.Sign(privateKey, message), nil
+	}
+	if isEmptyEd25519ctx(options) {
+		return nil, ErrEd25519Context
+	}
+	if options.Hash == crypto.SHA512 {
+		digest := sha512.Sum512(message)
+		message = digest[:]
+	}
+	return privateKey.Sign(nil, message, options)
+}
+
+func (sign *SignEd25519) verifyWithOptions(message, signature []byte, options *ed25519.Options) bool {
+	if len(sign.publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	if options == nil {
+		return ed25519.Verify(sign.publicKey, message, signature)
+	}
+	if isEmptyEd25519ctx(options) {
+		return false
+	}
+	if options.Hash == crypto.SHA512 {
+		digest := sha512.Sum512(message)
+		message = digest[:]
+	}
+	return ed25519.VerifyWithOptions(sign.publicKey, message, signature, options) == nil
+}
+
+// Ed25519ctx без контексту, який crypto/ed25519 виконав би як чистий Ed25519
+func isEmptyEd25519ctx(options *ed25519.Options) bool {
+	return options.Hash != crypto.SHA512 && options.Context == ""
+}
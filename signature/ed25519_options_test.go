@@ -0,0 +1,102 @@
+package signature_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+// Підписувач з сирих ключів RFC 8032 (seed у hex)
+func rfc8032Signer(t *testing.T, seedHex string) *signature.SignEd25519 {
+	seed, err := hex.DecodeString(seedHex)
+	assert.Nil(t, err)
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.Nil(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	assert.Nil(t, err)
+	sign, err := signature.NewSignEd25519("apy_key",
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})))
+	assert.Nil(t, err)
+	return sign
+}
+
+func hexToBase64(t *testing.T, value string) string {
+	decoded, err := hex.DecodeString(value)
+	assert.Nil(t, err)
+	return base64.StdEncoding.EncodeToString(decoded)
+}
+
+func hexToString(t *testing.T, value string) string {
+	decoded, err := hex.DecodeString(value)
+	assert.Nil(t, err)
+	return string(decoded)
+}
+
+// Test 1: RFC 8032 section 7.3 Ed25519ph test vector
+func TestEd25519ph(t *testing.T) {
+	sign := rfc8032Signer(t, "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42")
+	expected := hexToBase64(t, "98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae41"+
+		"31f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406")
+	options := signature.Ed25519phOptions("")
+	result, err := sign.CreateSignatureWithOptions("abc", options)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	assert.True(t, sign.ValidateSignatureWithOptions("abc", expected, options))
+	assert.False(t, sign.ValidateSignatureWithOptions("abd", expected, options))
+	// Підпис Ed25519ph не проходить перевірку чистим Ed25519
+	assert.False(t, sign.ValidateSignature("abc", expected))
+
+	ph := sign.WithOptions(options)
+	assert.Equal(t, expected, ph.CreateSignature("abc"))
+	assert.True(t, ph.ValidateSignature("abc", expected))
+}
+
+// Test 2: RFC 8032 section 7.2 Ed25519ctx test vector
+func TestEd25519ctx(t *testing.T) {
+	sign := rfc8032Signer(t, "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6")
+	message := hexToString(t, "f726936d19c800494e3fdaff20b276a8")
+	expected := hexToBase64(t, "55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a"+
+		"8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d")
+	options := signature.Ed25519ctxOptions("foo")
+	result, err := sign.CreateSignatureWithOptions(message, options)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	assert.True(t, sign.ValidateSignatureWithOptions(message, expected, options))
+	// Інший контекст - інший домен
+	assert.False(t, sign.ValidateSignatureWithOptions(message, expected, signature.Ed25519ctxOptions("bar")))
+
+	ctx := sign.WithOptions(options)
+	assert.Equal(t, expected, ctx.CreateSignature(message))
+	assert.True(t, ctx.ValidateSignature(message, expected))
+	assert.False(t, ctx.ValidateSignature(message, sign.CreateSignature(message)))
+}
+
+// Test 3: Invalid options, empty Ed25519ctx context and destroyed key
+func TestEd25519OptionsErrors(t *testing.T) {
+	sign := rfc8032Signer(t, "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6")
+	long := make([]byte, 256)
+	_, err := sign.CreateSignatureWithOptions("message", signature.Ed25519ctxOptions(string(long)))
+	assert.NotNil(t, err)
+	// Порожній контекст Ed25519ctx не підписує чистий Ed25519
+	_, err = sign.CreateSignatureWithOptions("message", signature.Ed25519ctxOptions(""))
+	assert.ErrorIs(t, err, signature.ErrEd25519Context)
+	pure := sign.CreateSignature("message")
+	assert.False(t, sign.ValidateSignatureWithOptions("message", pure, signature.Ed25519ctxOptions("")))
+	ctx := sign.WithOptions(signature.Ed25519ctxOptions(""))
+	assert.Empty(t, ctx.CreateSignature("message"))
+	_, err = ctx.CreateSignatureContext(context.Background(), "message")
+	assert.ErrorIs(t, err, signature.ErrEd25519Context)
+	assert.False(t, ctx.ValidateSignature("message", pure))
+	sign.Destroy()
+	_, err = sign.CreateSignatureWithOptions("message", signature.Ed25519phOptions(""))
+	assert.ErrorIs(t, err, signature.ErrEd25519PrivateKey)
+}
@@ -1,59 +1,189 @@
 package signature
 
 import (
-	"crypto/hmac"
+	"context"
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding"
 	"encoding/hex"
+	"hash"
+	"sync"
+	"unsafe"
+)
+
+// Довжина підпису HMAC-SHA256 у hex
+const HMACSignatureSize = sha256.Size * 2
 
-	"github.com/bitly/go-simplejson"
+type (
+	SignHMAC struct {
+		apiKey string
+		key    *hmacKey
+	}
+	// Секрет та знімки станів SHA-256 після key^ipad і key^opad, обчислені один раз.
+	// Стани з пулу відновлюються зі знімків і після підпису скидаються, тож похідні
+	// від ключа є лише у зрізах, які затирає Destroy. Destroy бере блокування на запис,
+	// тож підпис ніколи не бачить частково затертий секрет чи знімок.
+	hmacKey struct {
+		mutex     sync.RWMutex
+		secret    []byte
+		inner     []byte
+		outer     []byte
+		destroyed bool
+	}
+	hmacState struct {
+		inner hash.Hash
+		outer hash.Hash
+		sum   [sha256.Size]byte
+	}
 )
 
-type SignHMAC struct {
-	apiSecret string
-	apiKey    string
-}
+// Стани без ключа, спільні для всіх SignHMAC та HMACTemplate
+var hmacStates = sync.Pool{New: func() any {
+	return &hmacState{inner: sha256.New(), outer: sha256.New()}
+}}
 
 // Функція для створення підпису
 func (sign *SignHMAC) CreateSignature(queryString string) string {
-	h := hmac.New(sha256.New, []byte(sign.apiSecret))
-	h.Write([]byte(queryString))
-	return hex.EncodeToString(h.Sum(nil))
-}
-
-func (sign *SignHMAC) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
-	return signParameters(params, sign)
-}
-
-func (sign *SignHMAC) ValidateSignatureParams(params *simplejson.Json) (result bool) {
-	// Считування сігнатури
-	signature := params.Get("signature").MustString()
-	// Видалення підпису з параметрів
-	js, _ := params.MarshalJSON()
-	unsignedParams, _ := simplejson.NewJson(js)
-	unsignedParams.Del("signature")
-	expectedSignature := func() string {
-		paramsStr, err := ConvertSimpleJSONToString(unsignedParams)
-		if err != nil {
-			return ""
-		}
-		return paramsStr
+	var buffer [HMACSignatureSize]byte
+	signature, ok := sign.appendSignature(buffer[:0], stringBytes(queryString))
+	if !ok {
+		return ""
+	}
+	return string(signature)
+}
+
+// Функція для створення підпису з помилкою, після Destroy - ErrSignerDestroyed
+func (sign *SignHMAC) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	var buffer [HMACSignatureSize]byte
+	signature, ok := sign.appendSignature(buffer[:0], stringBytes(queryString))
+	if !ok {
+		return "", ErrSignerDestroyed
 	}
+	return string(signature), nil
+}
 
-	return sign.ValidateSignature(expectedSignature(), signature)
+// Функція для дописування hex підпису message у dst без алокацій,
+// якщо ємності dst вистачає (HMACSignatureSize байт)
+func (sign *SignHMAC) AppendSignature(dst, message []byte) []byte {
+	dst, _ = sign.appendSignature(dst, message)
+	return dst
+}
+
+// Підпис під блокуванням на читання, false - підписувач знищено
+func (sign *SignHMAC) appendSignature(dst, message []byte) ([]byte, bool) {
+	sign.key.mutex.RLock()
+	defer sign.key.mutex.RUnlock()
+	if sign.key.destroyed {
+		return dst, false
+	}
+	return appendHMAC(dst, sign.key.inner, sign.key.outer, message), true
+}
+
+// Підпис HMAC-SHA256 зі знімків inner та outer станом з пулу. Після підпису стан
+// скидається до початкового, щоб у пулі не лишалося похідних від ключа.
+func appendHMAC(dst, inner, outer, message []byte) []byte {
+	state := hmacStates.Get().(*hmacState)
+	// Помилка можлива лише для чужого знімка, знімки створені тим самим sha256
+	state.inner.(encoding.BinaryUnmarshaler).UnmarshalBinary(inner)
+	state.inner.Write(message)
+	state.inner.Sum(state.sum[:0])
+	state.outer.(encoding.BinaryUnmarshaler).UnmarshalBinary(outer)
+	state.outer.Write(state.sum[:])
+	state.outer.Sum(state.sum[:0])
+	dst = hex.AppendEncode(dst, state.sum[:])
+	state.inner.Reset()
+	state.outer.Reset()
+	clear(state.sum[:])
+	hmacStates.Put(state)
+	return dst
+}
+
+// Знімки станів SHA-256 після key^ipad з prefix та після key^opad (RFC 2104)
+func hmacSnapshots(secret, prefix []byte) (inner, outer []byte) {
+	// Ключ, довший за блок, спочатку хешується
+	key := make([]byte, sha256.BlockSize)
+	defer wipeBytes(key)
+	if len(secret) > sha256.BlockSize {
+		sum := sha256.Sum256(secret)
+		copy(key, sum[:])
+		clear(sum[:])
+	} else {
+		copy(key, secret)
+	}
+	pad := make([]byte, sha256.BlockSize)
+	defer wipeBytes(pad)
+	for i := range key {
+		pad[i] = key[i] ^ 0x36
+	}
+	state := sha256.New()
+	state.Write(pad)
+	state.Write(prefix)
+	inner = marshalHashState(state)
+	for i := range key {
+		pad[i] = key[i] ^ 0x5c
+	}
+	state.Reset()
+	state.Write(pad)
+	outer = marshalHashState(state)
+	state.Reset()
+	return inner, outer
+}
+
+// Стан sha256 завжди серіалізується, помилки MarshalBinary тут не буває
+func marshalHashState(state hash.Hash) []byte {
+	snapshot, _ := state.(encoding.BinaryMarshaler).MarshalBinary()
+	return snapshot
 }
 
 func (sign *SignHMAC) ValidateSignature(message, signature string) bool {
-	// Порівняння створеного підпису з наданим
-	return sign.CreateSignature(message) == signature
+	// Порівняння створеного підпису з наданим за сталий час
+	var buffer [HMACSignatureSize]byte
+	expected, ok := sign.appendSignature(buffer[:0], stringBytes(message))
+	return ok && subtle.ConstantTimeCompare(expected, stringBytes(signature)) == 1
 }
 
 func (sign *SignHMAC) GetAPIKey() string {
 	return sign.apiKey
 }
 
-func NewSignHMAC(apiKey PublicKey, apiSecret SecretKey) *SignHMAC {
-	return &SignHMAC{
-		apiSecret: string(apiSecret),
-		apiKey:    string(apiKey),
+// Функція для затирання секрету, після неї підписувач непридатний до використання
+func (sign *SignHMAC) Destroy() {
+	sign.key.mutex.Lock()
+	defer sign.key.mutex.Unlock()
+	for _, secret := range [][]byte{sign.key.secret, sign.key.inner, sign.key.outer} {
+		munlock(secret)
+		wipeBytes(secret)
 	}
+	sign.key.secret, sign.key.inner, sign.key.outer = nil, nil, nil
+	sign.key.destroyed = true
+}
+
+// Функція для закріплення секрету та знімків станів в оперативній пам'яті (mlock),
+// щоб вони не потрапили у swap
+func (sign *SignHMAC) Mlock() error {
+	sign.key.mutex.RLock()
+	defer sign.key.mutex.RUnlock()
+	for _, secret := range [][]byte{sign.key.secret, sign.key.inner, sign.key.outer} {
+		if err := mlock(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewSignHMAC(apiKey PublicKey, apiSecret SecretKey) *SignHMAC {
+	return NewSignHMACFromBytes(apiKey, []byte(apiSecret))
+}
+
+// Функція для створення підпису з секретом у байтах.
+// На відміну від рядка, переданий зріз буде затерто у Destroy.
+func NewSignHMACFromBytes(apiKey PublicKey, apiSecret []byte) *SignHMAC {
+	key := &hmacKey{secret: apiSecret}
+	key.inner, key.outer = hmacSnapshots(apiSecret, nil)
+	return &SignHMAC{apiKey: string(apiKey), key: key}
+}
+
+// Перегляд рядка як зрізу байтів без копіювання, зріз не можна змінювати
+func stringBytes(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
 }
@@ -55,3 +55,21 @@ func TestParamsValidateHMACWrongSignature(t *testing.T) {
 		assert.False(t, valid)
 	}()
 }
+
+// Test 10: Re-signing ignores and replaces an existing signature field
+func TestParamsResignHMAC(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	params.Set("signature", "stale_signature")
+	signedParams, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signedParams.Get("signature").MustString())
+	// Повторний підпис підписаних параметрів дає той самий підпис
+	signedParams, err = sign.SignParameters(signedParams)
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signedParams.Get("signature").MustString())
+	assert.True(t, sign.ValidateSignatureParams(signedParams))
+	// Вхідні параметри не змінюються
+	assert.Equal(t, "stale_signature", params.Get("signature").MustString())
+}
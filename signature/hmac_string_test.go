@@ -42,3 +42,71 @@ func TestStringValidateHMACWrongSignature(t *testing.T) {
 		assert.False(t, valid)
 	}()
 }
+
+// Test 4: Append HMAC signature into caller buffer
+func TestStringAppendSignatureHMAC(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	message := []byte("timestamp=1610612740000")
+	buffer := make([]byte, 0, 2*signature.HMACSignatureSize)
+	buffer = append(buffer, "signature="...)
+	buffer = sign.AppendSignature(buffer, message)
+	assert.Equal(t, "signature=b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", string(buffer))
+	// Після затирання ключа підпис не дописується
+	sign.Destroy()
+	assert.Empty(t, sign.AppendSignature(nil, message))
+}
+
+// Test 5: HMAC hot path does not allocate
+func TestStringHMACZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	text := "timestamp=1610612740000"
+	message := []byte(text)
+	expected := sign.CreateSignature(text)
+	buffer := make([]byte, 0, signature.HMACSignatureSize)
+	allocs := testing.AllocsPerRun(100, func() {
+		buffer = sign.AppendSignature(buffer[:0], message)
+	})
+	assert.Zero(t, allocs)
+	allocs = testing.AllocsPerRun(100, func() {
+		sign.ValidateSignature(text, expected)
+	})
+	assert.Zero(t, allocs)
+}
+
+// Test 6: Signers share pooled hash states without sharing key material
+func TestStringHMACDestroySharedStates(t *testing.T) {
+	first := signature.NewSignHMAC("apy_key", "apy_secret")
+	second := signature.NewSignHMAC("apy_key", "other_secret")
+	message := "timestamp=1610612740000"
+	expected := second.CreateSignature(message)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", first.CreateSignature(message))
+	first.Destroy()
+	// Стани з пулу скидаються після кожного підпису, тож інший ключ не зачіпається
+	assert.Equal(t, expected, second.CreateSignature(message))
+	assert.NotEqual(t, expected, signature.NewSignHMAC("apy_key", "apy_secret").CreateSignature(message))
+	assert.Empty(t, first.CreateSignature(message))
+	assert.False(t, first.ValidateSignature(message, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66"))
+}
+
+func BenchmarkCreateSignatureHMAC(b *testing.B) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		sign.CreateSignature("timestamp=1610612740000")
+	}
+}
+
+func BenchmarkAppendSignatureHMAC(b *testing.B) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	message := []byte("timestamp=1610612740000")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		buffer := make([]byte, 0, signature.HMACSignatureSize)
+		for pb.Next() {
+			buffer = sign.AppendSignature(buffer[:0], message)
+		}
+	})
+}
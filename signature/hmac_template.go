@@ -0,0 +1,72 @@
+package signature
+
+import "sync"
+
+// Шаблонний підпис HMAC-SHA256 для запитів зі спільним сталим префіксом
+// канонічного рядка (apiKey, symbol, side, type...). Префікс поглинається один раз,
+// стан SHA-256 зберігається через encoding.BinaryMarshaler, а кожен запит
+// дописує лише змінний суфікс (price, quantity, timestamp).
+
+type HMACTemplate struct {
+	prefix string
+	// Блокування на запис береться лише Destroy, як у SignHMAC
+	mutex     sync.RWMutex
+	destroyed bool
+	// Знімки станів SHA-256: inner - після key^ipad та префікса, outer - після key^opad
+	inner []byte
+	outer []byte
+}
+
+// Функція для створення шаблону, підпис якого дорівнює CreateSignature(prefix + suffix)
+func (sign *SignHMAC) NewTemplate(prefix string) (*HMACTemplate, error) {
+	sign.key.mutex.RLock()
+	defer sign.key.mutex.RUnlock()
+	if sign.key.destroyed {
+		return nil, ErrSignerDestroyed
+	}
+	template := &HMACTemplate{prefix: prefix}
+	template.inner, template.outer = hmacSnapshots(sign.key.secret, stringBytes(prefix))
+	return template, nil
+}
+
+func (template *HMACTemplate) Prefix() string {
+	return template.prefix
+}
+
+// Функція для створення hex підпису рядка prefix + suffix
+func (template *HMACTemplate) CreateSignature(suffix string) string {
+	var buffer [HMACSignatureSize]byte
+	signature, ok := template.appendSignature(buffer[:0], stringBytes(suffix))
+	if !ok {
+		return ""
+	}
+	return string(signature)
+}
+
+// Функція для дописування hex підпису рядка prefix + suffix у dst
+func (template *HMACTemplate) AppendSignature(dst, suffix []byte) []byte {
+	dst, _ = template.appendSignature(dst, suffix)
+	return dst
+}
+
+// Підпис під блокуванням на читання, false - шаблон знищено
+func (template *HMACTemplate) appendSignature(dst, suffix []byte) ([]byte, bool) {
+	template.mutex.RLock()
+	defer template.mutex.RUnlock()
+	if template.destroyed {
+		return dst, false
+	}
+	dst = appendHMAC(dst, template.inner, template.outer, suffix)
+	return dst, true
+}
+
+// Функція для затирання знімків станів, похідних від ключа
+func (template *HMACTemplate) Destroy() {
+	template.mutex.Lock()
+	defer template.mutex.Unlock()
+	wipeBytes(template.inner)
+	wipeBytes(template.outer)
+	template.inner = nil
+	template.outer = nil
+	template.destroyed = true
+}
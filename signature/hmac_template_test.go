@@ -0,0 +1,110 @@
+package signature_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+const templatePrefix = "apiKey=apy_key&newClientOrderId=my_order_id_1&newOrderRespType=ACK&" +
+	"recvWindow=5000&selfTradePreventionMode=EXPIRE_TAKER&side=SELL&symbol=BTCUSDT&timeInForce=GTC&type=LIMIT&"
+
+func templateSuffix(i int) string {
+	return "price=" + strconv.Itoa(52000+i) + ".00&quantity=0.01000000&timestamp=" + strconv.Itoa(1645423376532+i)
+}
+
+// Test 1: Template signature equals signature of the full string
+func TestHMACTemplate(t *testing.T) {
+	secrets := []string{"apy_secret", strings.Repeat("long_secret_", 10)}
+	for _, secret := range secrets {
+		sign := signature.NewSignHMAC("apy_key", signature.SecretKey(secret))
+		template, err := sign.NewTemplate(templatePrefix)
+		assert.Nil(t, err)
+		assert.Equal(t, templatePrefix, template.Prefix())
+		for i := 0; i < 3; i++ {
+			suffix := templateSuffix(i)
+			expected := sign.CreateSignature(templatePrefix + suffix)
+			assert.Equal(t, expected, template.CreateSignature(suffix))
+			assert.Equal(t, "signature="+expected, string(template.AppendSignature([]byte("signature="), []byte(suffix))))
+		}
+	}
+	// Порожній префікс дає звичайний HMAC
+	template, err := signature.NewSignHMAC("apy_key", "apy_secret").NewTemplate("")
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", template.CreateSignature("timestamp=1610612740000"))
+}
+
+// Test 2: Template of destroyed signer and destroyed template
+func TestHMACTemplateDestroy(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	template, err := sign.NewTemplate(templatePrefix)
+	assert.Nil(t, err)
+	template.Destroy()
+	assert.Empty(t, template.CreateSignature(templateSuffix(0)))
+	sign.Destroy()
+	_, err = sign.NewTemplate(templatePrefix)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+}
+
+// Test 3: Destroy during concurrent template signing (go test -race)
+func TestHMACTemplateDestroyConcurrent(t *testing.T) {
+	template, err := signature.NewSignHMAC("apy_key", "apy_secret").NewTemplate(templatePrefix)
+	assert.Nil(t, err)
+	expected := template.CreateSignature(templateSuffix(0))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if signed := template.CreateSignature(templateSuffix(0)); signed != "" {
+					assert.Equal(t, expected, signed)
+				}
+			}
+		}()
+	}
+	template.Destroy()
+	wg.Wait()
+	assert.Empty(t, template.CreateSignature(templateSuffix(0)))
+}
+
+// Test 4: Template hot path does not allocate
+func TestHMACTemplateZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items under the race detector")
+	}
+	template, err := signature.NewSignHMAC("apy_key", "apy_secret").NewTemplate(templatePrefix)
+	assert.Nil(t, err)
+	suffix := []byte(templateSuffix(0))
+	buffer := make([]byte, 0, signature.HMACSignatureSize)
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		buffer = template.AppendSignature(buffer[:0], suffix)
+	}))
+}
+
+func BenchmarkHMACFullString(b *testing.B) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	message := []byte(templatePrefix + templateSuffix(0))
+	buffer := make([]byte, 0, signature.HMACSignatureSize)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		buffer = sign.AppendSignature(buffer[:0], message)
+	}
+}
+
+func BenchmarkHMACTemplate(b *testing.B) {
+	template, err := signature.NewSignHMAC("apy_key", "apy_secret").NewTemplate(templatePrefix)
+	if err != nil {
+		b.Fatal(err)
+	}
+	suffix := []byte(templateSuffix(0))
+	buffer := make([]byte, 0, signature.HMACSignatureSize)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		buffer = template.AppendSignature(buffer[:0], suffix)
+	}
+}
@@ -0,0 +1,4 @@
+// Пакет hsm містить підписувач signature.Sign, ключі якого зберігаються
+// в апаратному модулі безпеки (HSM) і доступні через PKCS#11.
+// Для роботи потрібен cgo; для тестів використовується SoftHSM2.
+package hsm
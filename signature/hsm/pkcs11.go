@@ -0,0 +1,347 @@
+//go:build cgo
+
+package hsm
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+// Константи EdDSA з PKCS#11 v3.0, відсутні у github.com/miekg/pkcs11
+const (
+	CKK_EC_EDWARDS              = 0x00000040
+	CKM_EC_EDWARDS_KEY_PAIR_GEN = 0x00001055
+	CKM_EDDSA                   = 0x00001057
+)
+
+var (
+	ErrTokenNotFound = errors.New("pkcs11 token not found")
+	ErrKeyNotFound   = errors.New("pkcs11 key not found")
+	ErrKeyType       = errors.New("unsupported pkcs11 key type")
+	ErrClosed        = errors.New("pkcs11 signer is closed")
+)
+
+var (
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+	oidEd25519        = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+type (
+	Config struct {
+		Module     string // шлях до бібліотеки PKCS#11, напр. /usr/lib/softhsm/libsofthsm2.so
+		TokenLabel string // мітка токена у слоті
+		PIN        string // PIN користувача
+		KeyLabel   string // мітка ключа (CKA_LABEL), необов'язкова якщо задано KeyID
+		KeyID      []byte // ідентифікатор ключа (CKA_ID), необов'язковий якщо задано KeyLabel
+		APIKey     string
+	}
+	// Підписувач з ключем RSA, ECDSA або Ed25519 у токені PKCS#11.
+	// Підпис виконується модулем, перевірка - публічним ключем, експортованим з токена.
+	SignPKCS11 struct {
+		mutex      sync.Mutex
+		ctx        *pkcs11.Ctx
+		session    pkcs11.SessionHandle
+		privateKey pkcs11.ObjectHandle
+		publicKey  crypto.PublicKey
+		apiKey     string
+		closed     bool
+	}
+)
+
+func NewSignPKCS11(config Config) (sign *SignPKCS11, err error) {
+	if config.KeyLabel == "" && len(config.KeyID) == 0 {
+		return nil, fmt.Errorf("%w: key label or id is required", ErrKeyNotFound)
+	}
+	ctx := pkcs11.New(config.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load pkcs11 module %s", config.Module)
+	}
+	if err = ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+	sign = &SignPKCS11{ctx: ctx, apiKey: config.APIKey}
+	defer func() {
+		if err != nil {
+			sign.Close()
+			sign = nil
+		}
+	}()
+
+	slot, err := findSlot(ctx, config.TokenLabel)
+	if err != nil {
+		return
+	}
+	if sign.session, err = ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION); err != nil {
+		return
+	}
+	if err = ctx.Login(sign.session, pkcs11.CKU_USER, config.PIN); err != nil {
+		return
+	}
+	if sign.privateKey, err = sign.findObject(pkcs11.CKO_PRIVATE_KEY, config.KeyLabel, config.KeyID); err != nil {
+		return
+	}
+	publicKey, err := sign.findObject(pkcs11.CKO_PUBLIC_KEY, config.KeyLabel, config.KeyID)
+	if err != nil {
+		return
+	}
+	sign.publicKey, err = sign.exportPublicKey(publicKey)
+	return
+}
+
+// Функція для створення підпису модулем PKCS#11
+func (sign *SignPKCS11) Sign(message []byte) ([]byte, error) {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	if sign.closed {
+		return nil, ErrClosed
+	}
+	switch sign.publicKey.(type) {
+	case *rsa.PublicKey:
+		return sign.sign(pkcs11.CKM_SHA256_RSA_PKCS, message)
+	case ed25519.PublicKey:
+		return sign.sign(CKM_EDDSA, message)
+	case *ecdsa.PublicKey:
+		hashed := sha256.Sum256(message)
+		raw, err := sign.sign(pkcs11.CKM_ECDSA, hashed[:])
+		if err != nil {
+			return nil, err
+		}
+		// Модуль повертає r||s, перетворюємо у ASN.1 як crypto/ecdsa
+		half := len(raw) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			R: new(big.Int).SetBytes(raw[:half]),
+			S: new(big.Int).SetBytes(raw[half:]),
+		})
+	}
+	return nil, ErrKeyType
+}
+
+// Функція для створення підпису у Base64, як у signature.SignRSA та signature.SignEd25519
+func (sign *SignPKCS11) CreateSignature(queryString string) string {
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
+}
+
+// Функція для створення підпису з помилкою модуля, яку SignParams повертає викликачу
+func (sign *SignPKCS11) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	signature, err := sign.Sign([]byte(queryString))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Функція для валідації підпису публічним ключем з токена
+func (sign *SignPKCS11) ValidateSignature(message, signature string) bool {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch publicKey := sign.publicKey.(type) {
+	case *rsa.PublicKey:
+		hashed := sha256.Sum256([]byte(message))
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signatureBytes) == nil
+	case *ecdsa.PublicKey:
+		hashed := sha256.Sum256([]byte(message))
+		return ecdsa.VerifyASN1(publicKey, hashed[:], signatureBytes)
+	case ed25519.PublicKey:
+		return ed25519.Verify(publicKey, []byte(message), signatureBytes)
+	}
+	return false
+}
+
+func (sign *SignPKCS11) GetAPIKey() string {
+	return sign.apiKey
+}
+
+// Публічний ключ, експортований з токена: *rsa.PublicKey, *ecdsa.PublicKey або ed25519.PublicKey
+func (sign *SignPKCS11) PublicKey() crypto.PublicKey {
+	return sign.publicKey
+}
+
+// Функція для завершення сесії та вивантаження модуля
+func (sign *SignPKCS11) Close() error {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	if sign.closed {
+		return nil
+	}
+	sign.closed = true
+	if sign.session != 0 {
+		sign.ctx.Logout(sign.session)
+		sign.ctx.CloseSession(sign.session)
+	}
+	err := sign.ctx.Finalize()
+	sign.ctx.Destroy()
+	return err
+}
+
+// Реалізація signature.Destroyer: ключ залишається в токені, закривається лише сесія
+func (sign *SignPKCS11) Destroy() {
+	sign.Close()
+}
+
+func (sign *SignPKCS11) sign(mechanism uint, message []byte) ([]byte, error) {
+	if err := sign.ctx.SignInit(sign.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, sign.privateKey); err != nil {
+		return nil, err
+	}
+	return sign.ctx.Sign(sign.session, message)
+}
+
+func findSlot(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if strings.TrimRight(info.Label, " \x00") == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrTokenNotFound, label)
+}
+
+func (sign *SignPKCS11) findObject(class uint, label string, id []byte) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_CLASS, class)}
+	if label != "" {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_LABEL, label))
+	}
+	if len(id) > 0 {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_ID, id))
+	}
+	if err := sign.ctx.FindObjectsInit(sign.session, template); err != nil {
+		return 0, err
+	}
+	objects, _, err := sign.ctx.FindObjects(sign.session, 1)
+	sign.ctx.FindObjectsFinal(sign.session)
+	if err != nil {
+		return 0, err
+	}
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("%w: label %q id %x", ErrKeyNotFound, label, id)
+	}
+	return objects[0], nil
+}
+
+func (sign *SignPKCS11) exportPublicKey(object pkcs11.ObjectHandle) (crypto.PublicKey, error) {
+	attributes, err := sign.ctx.GetAttributeValue(sign.session, object, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch keyType := attributeUint(attributes[0].Value); keyType {
+	case pkcs11.CKK_RSA:
+		attributes, err := sign.ctx.GetAttributeValue(sign.session, object, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(attributes[0].Value),
+			E: int(new(big.Int).SetBytes(attributes[1].Value).Int64()),
+		}, nil
+	case pkcs11.CKK_EC, CKK_EC_EDWARDS:
+		attributes, err := sign.ctx.GetAttributeValue(sign.session, object, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+		})
+		if err != nil {
+			return nil, err
+		}
+		// CKA_EC_POINT зазвичай загорнуто у DER OCTET STRING
+		point := attributes[1].Value
+		var unwrapped []byte
+		if rest, err := asn1.Unmarshal(point, &unwrapped); err == nil && len(rest) == 0 {
+			point = unwrapped
+		}
+		if keyType == CKK_EC_EDWARDS {
+			// CKK_EC_EDWARDS охоплює також Ed448, крива визначається CKA_EC_PARAMS
+			if !isEd25519Params(attributes[0].Value) {
+				return nil, fmt.Errorf("%w: Edwards curve is not Ed25519", ErrKeyType)
+			}
+			if len(point) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("%w: bad Ed25519 point size %d", ErrKeyType, len(point))
+			}
+			return ed25519.PublicKey(point), nil
+		}
+		return ecdsaPublicKey(attributes[0].Value, point)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrKeyType, keyType)
+	}
+}
+
+// CKA_EC_PARAMS кривої Ed25519: OID id-Ed25519 або, за PKCS#11 v3.0, назва кривої
+func isEd25519Params(params []byte) bool {
+	var oid asn1.ObjectIdentifier
+	if rest, err := asn1.Unmarshal(params, &oid); err == nil && len(rest) == 0 {
+		return oid.Equal(oidEd25519)
+	}
+	var name string
+	if rest, err := asn1.Unmarshal(params, &name); err == nil && len(rest) == 0 {
+		return name == "edwards25519"
+	}
+	return false
+}
+
+func ecdsaPublicKey(params, point []byte) (*ecdsa.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return nil, fmt.Errorf("%w: bad EC params: %v", ErrKeyType, err)
+	}
+	var curve elliptic.Curve
+	switch {
+	case oid.Equal(oidNamedCurveP256):
+		curve = elliptic.P256()
+	case oid.Equal(oidNamedCurveP384):
+		curve = elliptic.P384()
+	case oid.Equal(oidNamedCurveP521):
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("%w: curve %v", ErrKeyType, oid)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(point) != 1+2*size || point[0] != 4 {
+		return nil, fmt.Errorf("%w: bad EC point", ErrKeyType)
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(point[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(point[1+size:]),
+	}, nil
+}
+
+// CK_ULONG у нативному порядку байтів
+func attributeUint(value []byte) uint {
+	switch len(value) {
+	case 4:
+		return uint(binary.NativeEndian.Uint32(value))
+	case 8:
+		return uint(binary.NativeEndian.Uint64(value))
+	}
+	return ^uint(0)
+}
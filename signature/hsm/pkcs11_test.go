@@ -0,0 +1,193 @@
+//go:build cgo
+
+package hsm_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/hsm"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tokenLabel = "turbo-signer"
+	userPIN    = "1234"
+	message    = "timestamp=1610612740000"
+)
+
+// Функція для ініціалізації локального токена SoftHSM2 у тимчасовому каталозі.
+// Шлях до модуля можна задати змінною SOFTHSM2_MODULE.
+func initSoftHSM(t *testing.T) string {
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		for _, candidate := range []string{
+			"/usr/lib/softhsm/libsofthsm2.so",
+			"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+			"/usr/local/lib/softhsm/libsofthsm2.so",
+			"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+		} {
+			if _, err := os.Stat(candidate); err == nil {
+				module = candidate
+				break
+			}
+		}
+	}
+	util, err := exec.LookPath("softhsm2-util")
+	if module == "" || err != nil {
+		t.Skip("SoftHSM2 is not installed")
+	}
+
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	assert.Nil(t, os.Mkdir(tokens, 0o700))
+	config := filepath.Join(dir, "softhsm2.conf")
+	assert.Nil(t, os.WriteFile(config, []byte("directories.tokendir = "+tokens+"\nobjectstore.backend = file\n"), 0o600))
+	t.Setenv("SOFTHSM2_CONF", config)
+	output, err := exec.Command(util, "--init-token", "--free", "--label", tokenLabel, "--pin", userPIN, "--so-pin", "5678").CombinedOutput()
+	assert.Nil(t, err, string(output))
+	return module
+}
+
+// Функція для генерації пари ключів у токені
+func generateKeyPair(t *testing.T, module, label string, mechanism uint, public []*pkcs11.Attribute) {
+	ctx := pkcs11.New(module)
+	assert.Nil(t, ctx.Initialize())
+	defer ctx.Destroy()
+	defer ctx.Finalize()
+	slots, err := ctx.GetSlotList(true)
+	assert.Nil(t, err)
+	var slot uint
+	for _, candidate := range slots {
+		if info, err := ctx.GetTokenInfo(candidate); err == nil && strings.TrimRight(info.Label, " \x00") == tokenLabel {
+			slot = candidate
+		}
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	assert.Nil(t, err)
+	defer ctx.CloseSession(session)
+	assert.Nil(t, ctx.Login(session, pkcs11.CKU_USER, userPIN))
+	defer ctx.Logout(session)
+
+	public = append(public,
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(label)))
+	private := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(label)),
+	}
+	_, _, err = ctx.GenerateKeyPair(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, public, private)
+	assert.Nil(t, err)
+}
+
+func mustDecode(t *testing.T, signature string) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	assert.Nil(t, err)
+	return decoded
+}
+
+func curveParams(t *testing.T, oid asn1.ObjectIdentifier) []byte {
+	params, err := asn1.Marshal(oid)
+	assert.Nil(t, err)
+	return params
+}
+
+// Test 1: RSA key in SoftHSM, compatible with signature.SignRSA encoding
+func TestPKCS11RSA(t *testing.T) {
+	module := initSoftHSM(t)
+	generateKeyPair(t, module, "rsa", pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, 2048),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+	})
+	sign, err := hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: tokenLabel, PIN: userPIN, KeyLabel: "rsa", APIKey: "apy_key"})
+	assert.Nil(t, err)
+	defer sign.Close()
+	assert.IsType(t, &rsa.PublicKey{}, sign.PublicKey())
+
+	signed := sign.CreateSignature(message)
+	assert.True(t, sign.ValidateSignature(message, signed))
+	assert.False(t, sign.ValidateSignature(message+"1", signed))
+
+	// Кодування збігається з signature.SignRSA: Base64 від PKCS#1 v1.5 з SHA-256
+	hashed := sha256.Sum256([]byte(message))
+	assert.Nil(t, rsa.VerifyPKCS1v15(sign.PublicKey().(*rsa.PublicKey), crypto.SHA256, hashed[:], mustDecode(t, signed)))
+
+	params := signature.NewParams().SetInt64("timestamp", 1610612740000)
+	params, err = signature.SignParams(params, sign)
+	assert.Nil(t, err)
+	assert.True(t, signature.ValidateParams(params, sign))
+
+	// Помилка модуля повертається SignParams
+	assert.Nil(t, sign.Close())
+	_, err = signature.SignParams(params, sign)
+	assert.ErrorIs(t, err, hsm.ErrClosed)
+}
+
+// Test 2: ECDSA P-256 key in SoftHSM, found by key ID
+func TestPKCS11ECDSA(t *testing.T) {
+	module := initSoftHSM(t)
+	generateKeyPair(t, module, "ecdsa", pkcs11.CKM_EC_KEY_PAIR_GEN, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, curveParams(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})),
+	})
+	sign, err := hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: tokenLabel, PIN: userPIN, KeyID: []byte("ecdsa")})
+	assert.Nil(t, err)
+	defer sign.Close()
+	assert.IsType(t, &ecdsa.PublicKey{}, sign.PublicKey())
+	signed := sign.CreateSignature(message)
+	assert.True(t, sign.ValidateSignature(message, signed))
+	assert.False(t, sign.ValidateSignature(message, "wrong_signature"))
+}
+
+// Test 3: Ed25519 key in SoftHSM
+func TestPKCS11Ed25519(t *testing.T) {
+	module := initSoftHSM(t)
+	generateKeyPair(t, module, "ed25519", hsm.CKM_EC_EDWARDS_KEY_PAIR_GEN, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, curveParams(t, asn1.ObjectIdentifier{1, 3, 101, 112})),
+	})
+	sign, err := hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: tokenLabel, PIN: userPIN, KeyLabel: "ed25519"})
+	assert.Nil(t, err)
+	defer sign.Close()
+	publicKey, ok := sign.PublicKey().(ed25519.PublicKey)
+	assert.True(t, ok)
+	signed := sign.CreateSignature(message)
+	assert.True(t, sign.ValidateSignature(message, signed))
+	assert.True(t, ed25519.Verify(publicKey, []byte(message), mustDecode(t, signed)))
+}
+
+// Test 4: Ed448 key is rejected, not treated as Ed25519
+func TestPKCS11Ed448(t *testing.T) {
+	module := initSoftHSM(t)
+	generateKeyPair(t, module, "ed448", hsm.CKM_EC_EDWARDS_KEY_PAIR_GEN, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, curveParams(t, asn1.ObjectIdentifier{1, 3, 101, 113})),
+	})
+	_, err := hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: tokenLabel, PIN: userPIN, KeyLabel: "ed448"})
+	assert.ErrorIs(t, err, hsm.ErrKeyType)
+}
+
+// Test 5: Missing key and token
+func TestPKCS11Errors(t *testing.T) {
+	module := initSoftHSM(t)
+	_, err := hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: tokenLabel, PIN: userPIN, KeyLabel: "missing"})
+	assert.ErrorIs(t, err, hsm.ErrKeyNotFound)
+	_, err = hsm.NewSignPKCS11(hsm.Config{Module: module, TokenLabel: "missing", PIN: userPIN, KeyLabel: "rsa"})
+	assert.ErrorIs(t, err, hsm.ErrTokenNotFound)
+}
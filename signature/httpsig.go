@@ -0,0 +1,703 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP Message Signatures (RFC 9421)
+// Підпис формується над "signature base" з обраних компонентів запиту
+// та передається у заголовках Signature-Input і Signature.
+
+const (
+	HTTPSigHMACSHA256   = "hmac-sha256"
+	HTTPSigRSAv15SHA256 = "rsa-v1_5-sha256"
+	HTTPSigRSAPSSSHA512 = "rsa-pss-sha512"
+	HTTPSigEd25519      = "ed25519"
+)
+
+// Допустиме розходження годинників підписувача та перевірника за замовчуванням
+const DefaultHTTPSignatureClockSkew = time.Minute
+
+var (
+	ErrHTTPSignatureMissing   = errors.New("http signature not found")
+	ErrHTTPSignatureInvalid   = errors.New("http signature is invalid")
+	ErrHTTPSignatureExpired   = errors.New("http signature is expired")
+	ErrHTTPSignatureFuture    = errors.New("http signature is created in the future")
+	ErrHTTPSignatureAlgorithm = errors.New("http signature algorithm is not supported by signer")
+	ErrContentDigestMismatch  = errors.New("content-digest does not match message body")
+)
+
+type (
+	// Параметри підпису HTTP повідомлення
+	HTTPSignatureOptions struct {
+		Label      string    // мітка підпису, за замовчуванням "sig"
+		KeyID      string    // параметр keyid
+		Algorithm  string    // параметр alg; якщо порожній, визначається за типом Sign і не передається
+		Components []string  // компоненти, що покриваються підписом, напр. "@method", "@target-uri", "content-digest"
+		Created    time.Time // якщо нульовий, використовується поточний час
+		Expires    time.Time // необов'язковий
+		Nonce      string    // необов'язковий
+		Tag        string    // необов'язковий
+	}
+	HTTPSigner struct {
+		sign    Sign
+		options HTTPSignatureOptions
+		now     func() time.Time
+	}
+	// Функція пошуку ключа перевірки за keyid
+	HTTPKeyResolver func(keyID string) (Sign, error)
+	HTTPVerifier    struct {
+		resolve   HTTPKeyResolver
+		required  []string
+		now       func() time.Time
+		skew      time.Duration
+		maxAge    time.Duration
+		algorithm string
+	}
+	httpSigParam struct {
+		key   string
+		value string // серіалізоване значення
+	}
+	httpSigParams struct {
+		components []string
+		params     []httpSigParam
+	}
+)
+
+func NewHTTPSigner(sign Sign, options HTTPSignatureOptions) (*HTTPSigner, error) {
+	if options.Label == "" {
+		options.Label = "sig"
+	}
+	algorithm := options.Algorithm
+	if algorithm == "" {
+		algorithm = httpSigDefaultAlgorithm(sign)
+	}
+	if !httpSigSupports(sign, algorithm) {
+		return nil, fmt.Errorf("%w: %s", ErrHTTPSignatureAlgorithm, algorithm)
+	}
+	for _, component := range options.Components {
+		if component != strings.ToLower(component) {
+			return nil, fmt.Errorf("component identifier must be lowercase: %s", component)
+		}
+	}
+	return &HTTPSigner{sign: sign, options: options, now: time.Now}, nil
+}
+
+// Функція для заміни годинника, яким позначається created без HTTPSignatureOptions.Created.
+// Викликається до початку використання підписувача.
+func (signer *HTTPSigner) SetClock(now func() time.Time) {
+	signer.now = now
+}
+
+// Функція для підпису HTTP запиту, додає заголовки Signature-Input та Signature
+func (signer *HTTPSigner) SignRequest(req *http.Request) error {
+	opts := signer.options
+	if httpSigCovers(opts.Components, "content-digest") && req.Header.Get("Content-Digest") == "" {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Digest", ContentDigest(body))
+	}
+
+	created := opts.Created
+	if created.IsZero() {
+		created = signer.now()
+	}
+	sigParams := httpSigParams{components: opts.Components}
+	sigParams.add("created", strconv.FormatInt(created.Unix(), 10))
+	if !opts.Expires.IsZero() {
+		sigParams.add("expires", strconv.FormatInt(opts.Expires.Unix(), 10))
+	}
+	if opts.KeyID != "" {
+		sigParams.add("keyid", sfString(opts.KeyID))
+	}
+	if opts.Nonce != "" {
+		sigParams.add("nonce", sfString(opts.Nonce))
+	}
+	if opts.Algorithm != "" {
+		sigParams.add("alg", sfString(opts.Algorithm))
+	}
+	if opts.Tag != "" {
+		sigParams.add("tag", sfString(opts.Tag))
+	}
+
+	base, err := HTTPSignatureBase(req, sigParams.components, sigParams.String())
+	if err != nil {
+		return err
+	}
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = httpSigDefaultAlgorithm(signer.sign)
+	}
+	signature, err := httpSigCreate(req.Context(), signer.sign, algorithm, base)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Signature-Input", opts.Label+"="+sigParams.String())
+	req.Header.Add("Signature", opts.Label+"=:"+base64.StdEncoding.EncodeToString(signature)+":")
+	return nil
+}
+
+// required - компоненти, які обов'язково мають бути покриті підписом
+func NewHTTPVerifier(resolve HTTPKeyResolver, required ...string) *HTTPVerifier {
+	return &HTTPVerifier{resolve: resolve, required: required, now: time.Now, skew: DefaultHTTPSignatureClockSkew}
+}
+
+// Функція для заміни годинника, за яким перевіряються created та expires.
+// Викликається до початку використання перевірника.
+func (verifier *HTTPVerifier) SetClock(now func() time.Time) {
+	verifier.now = now
+}
+
+// Функція для встановлення допустимого розходження годинників для created та expires.
+// Викликається до початку використання перевірника.
+func (verifier *HTTPVerifier) SetClockSkew(skew time.Duration) {
+	verifier.skew = skew
+}
+
+// Функція для обмеження віку підпису за параметром created, 0 - без обмеження.
+// Підписи без created при цьому відхиляються. Викликається до початку використання перевірника.
+func (verifier *HTTPVerifier) SetMaxAge(maxAge time.Duration) {
+	verifier.maxAge = maxAge
+}
+
+// Функція для встановлення алгоритму підписів без параметра alg (напр. rsa-pss-sha512),
+// порожній - визначається за типом Sign. Викликається до початку використання перевірника.
+func (verifier *HTTPVerifier) SetAlgorithm(algorithm string) {
+	verifier.algorithm = algorithm
+}
+
+// Функція для перевірки підпису HTTP запиту.
+// Якщо label порожній, перевіряється перший підпис з Signature-Input.
+func (verifier *HTTPVerifier) VerifyRequest(req *http.Request, label string) error {
+	inputs, err := parseSFDictionary(strings.Join(req.Header.Values("Signature-Input"), ", "))
+	if err != nil {
+		return err
+	}
+	signatures, err := parseSFDictionary(strings.Join(req.Header.Values("Signature"), ", "))
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return ErrHTTPSignatureMissing
+	}
+	if label == "" {
+		label = inputs[0].key
+	}
+	input, ok := sfLookup(inputs, label)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHTTPSignatureMissing, label)
+	}
+	rawSignature, ok := sfLookup(signatures, label)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHTTPSignatureMissing, label)
+	}
+	signature, err := parseSFByteSequence(rawSignature)
+	if err != nil {
+		return err
+	}
+	sigParams, err := parseSignatureInput(input)
+	if err != nil {
+		return err
+	}
+	for _, component := range verifier.required {
+		if !httpSigCovers(sigParams.components, component) {
+			return fmt.Errorf("%w: component %s is not covered", ErrHTTPSignatureInvalid, component)
+		}
+	}
+	if err := verifier.checkTime(sigParams); err != nil {
+		return err
+	}
+
+	keyID, _ := sigParams.get("keyid")
+	keyID, _ = parseSFString(keyID)
+	sign, err := verifier.resolve(keyID)
+	if err != nil {
+		return err
+	}
+	algorithm := verifier.algorithm
+	if algorithm == "" {
+		algorithm = httpSigDefaultAlgorithm(sign)
+	}
+	if alg, ok := sigParams.get("alg"); ok {
+		algorithm, _ = parseSFString(alg)
+	}
+	if !httpSigSupports(sign, algorithm) {
+		return fmt.Errorf("%w: %s", ErrHTTPSignatureAlgorithm, algorithm)
+	}
+
+	if httpSigCovers(sigParams.components, "content-digest") {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return err
+		}
+		if err := VerifyContentDigest(req.Header.Get("Content-Digest"), body); err != nil {
+			return err
+		}
+	}
+
+	base, err := HTTPSignatureBase(req, sigParams.components, sigParams.String())
+	if err != nil {
+		return err
+	}
+	if !httpSigVerify(sign, algorithm, base, signature) {
+		return ErrHTTPSignatureInvalid
+	}
+	return nil
+}
+
+// Перевірка created та expires з урахуванням розходження годинників
+func (verifier *HTTPVerifier) checkTime(sigParams *httpSigParams) error {
+	now := verifier.now()
+	if created, ok := sigParams.get("created"); ok {
+		createdAt, err := strconv.ParseInt(created, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad created parameter", ErrHTTPSignatureInvalid)
+		}
+		age := now.Sub(time.Unix(createdAt, 0))
+		if age < -verifier.skew {
+			return fmt.Errorf("%w: created %d", ErrHTTPSignatureFuture, createdAt)
+		}
+		if verifier.maxAge > 0 && age > verifier.maxAge+verifier.skew {
+			return fmt.Errorf("%w: created %d is older than %s", ErrHTTPSignatureExpired, createdAt, verifier.maxAge)
+		}
+	} else if verifier.maxAge > 0 {
+		return fmt.Errorf("%w: created parameter is required", ErrHTTPSignatureInvalid)
+	}
+	if expires, ok := sigParams.get("expires"); ok {
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad expires parameter", ErrHTTPSignatureInvalid)
+		}
+		if now.Add(-verifier.skew).Unix() > expiresAt {
+			return ErrHTTPSignatureExpired
+		}
+	}
+	return nil
+}
+
+// Функція для побудови signature base (RFC 9421, розділ 2.5)
+func HTTPSignatureBase(req *http.Request, components []string, signatureParams string) (string, error) {
+	var base strings.Builder
+	for _, component := range components {
+		value, err := httpSigComponentValue(req, component)
+		if err != nil {
+			return "", err
+		}
+		base.WriteString(sfString(component))
+		base.WriteString(": ")
+		base.WriteString(value)
+		base.WriteByte('\n')
+	}
+	base.WriteString(`"@signature-params": `)
+	base.WriteString(signatureParams)
+	return base.String(), nil
+}
+
+// Функція для обчислення заголовка Content-Digest (RFC 9530) з SHA-256
+func ContentDigest(body []byte) string {
+	digest := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(digest[:]) + ":"
+}
+
+// Функція для перевірки заголовка Content-Digest, підтримуються sha-256 та sha-512
+func VerifyContentDigest(header string, body []byte) error {
+	digests, err := parseSFDictionary(header)
+	if err != nil {
+		return err
+	}
+	checked := false
+	for _, digest := range digests {
+		var expected []byte
+		switch digest.key {
+		case "sha-256":
+			sum := sha256.Sum256(body)
+			expected = sum[:]
+		case "sha-512":
+			sum := sha512.Sum512(body)
+			expected = sum[:]
+		default:
+			continue
+		}
+		actual, err := parseSFByteSequence(digest.value)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(expected, actual) {
+			return ErrContentDigestMismatch
+		}
+		checked = true
+	}
+	if !checked {
+		return fmt.Errorf("%w: no supported digest algorithm", ErrContentDigestMismatch)
+	}
+	return nil
+}
+
+func httpSigComponentValue(req *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return req.Method, nil
+	case "@target-uri":
+		return httpRequestScheme(req) + "://" + httpRequestAuthority(req) + req.URL.RequestURI(), nil
+	case "@authority":
+		return httpRequestAuthority(req), nil
+	case "@scheme":
+		return httpRequestScheme(req), nil
+	case "@request-target":
+		return req.URL.RequestURI(), nil
+	case "@path":
+		path := req.URL.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		return path, nil
+	case "@query":
+		return "?" + req.URL.RawQuery, nil
+	}
+	if strings.HasPrefix(component, "@") {
+		return "", fmt.Errorf("unsupported derived component: %s", component)
+	}
+	values := req.Header.Values(component)
+	if len(values) == 0 {
+		return "", fmt.Errorf("header %s is not present in request", component)
+	}
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+func httpRequestScheme(req *http.Request) string {
+	if req.URL.Scheme != "" {
+		return strings.ToLower(req.URL.Scheme)
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func httpRequestAuthority(req *http.Request) string {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	host = strings.ToLower(host)
+	switch scheme := httpRequestScheme(req); {
+	case scheme == "http" && strings.HasSuffix(host, ":80"):
+		host = strings.TrimSuffix(host, ":80")
+	case scheme == "https" && strings.HasSuffix(host, ":443"):
+		host = strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+// Функція для зчитування тіла запиту з його відновленням
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %v", err)
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func httpSigCovers(components []string, component string) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+func httpSigDefaultAlgorithm(sign Sign) string {
+	switch sign := sign.(type) {
+	case *SignHMAC:
+		return HTTPSigHMACSHA256
+	case *SignRSA:
+		return HTTPSigRSAv15SHA256
+	case *SignEd25519:
+		// Ed25519ph та Ed25519ctx не мають ідентифікаторів у RFC 9421
+		if sign.options == nil {
+			return HTTPSigEd25519
+		}
+	}
+	return ""
+}
+
+func httpSigSupports(sign Sign, algorithm string) bool {
+	switch sign := sign.(type) {
+	case *SignHMAC:
+		return algorithm == HTTPSigHMACSHA256
+	case *SignRSA:
+		return algorithm == HTTPSigRSAv15SHA256 || algorithm == HTTPSigRSAPSSSHA512
+	case *SignEd25519:
+		return algorithm == HTTPSigEd25519 && sign.options == nil
+	}
+	return false
+}
+
+// Функція для створення підпису signature base відповідним Sign. Невдача підписувача
+// (знищений ключ, лише публічний ключ) повертається помилкою, а не порожнім підписом.
+func httpSigCreate(ctx context.Context, sign Sign, algorithm, base string) ([]byte, error) {
+	var (
+		signature string
+		err       error
+	)
+	switch algorithm {
+	case HTTPSigHMACSHA256, HTTPSigRSAv15SHA256, HTTPSigEd25519:
+		signature, err = createSignature(ctx, sign, base)
+	case HTTPSigRSAPSSSHA512:
+		signature, err = sign.(*SignRSA).createSignaturePSS(base)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrHTTPSignatureAlgorithm, algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == HTTPSigHMACSHA256 {
+		return hex.DecodeString(signature)
+	}
+	return base64.StdEncoding.DecodeString(signature)
+}
+
+// Перевірка делегується Sign, який сам відхиляє підпис після Destroy
+func httpSigVerify(sign Sign, algorithm, base string, signature []byte) bool {
+	switch algorithm {
+	case HTTPSigHMACSHA256:
+		return len(signature) > 0 && sign.ValidateSignature(base, hex.EncodeToString(signature))
+	case HTTPSigRSAPSSSHA512:
+		return sign.(*SignRSA).ValidateSignaturePSS(base, base64.StdEncoding.EncodeToString(signature))
+	case HTTPSigRSAv15SHA256, HTTPSigEd25519:
+		return sign.ValidateSignature(base, base64.StdEncoding.EncodeToString(signature))
+	}
+	return false
+}
+
+func (p *httpSigParams) add(key, value string) {
+	p.params = append(p.params, httpSigParam{key: key, value: value})
+}
+
+func (p *httpSigParams) get(key string) (string, bool) {
+	for _, param := range p.params {
+		if param.key == key {
+			return param.value, true
+		}
+	}
+	return "", false
+}
+
+// Серіалізація значення @signature-params
+func (p *httpSigParams) String() string {
+	var out strings.Builder
+	out.WriteByte('(')
+	for i, component := range p.components {
+		if i > 0 {
+			out.WriteByte(' ')
+		}
+		out.WriteString(sfString(component))
+	}
+	out.WriteByte(')')
+	for _, param := range p.params {
+		out.WriteByte(';')
+		out.WriteString(param.key)
+		out.WriteByte('=')
+		out.WriteString(param.value)
+	}
+	return out.String()
+}
+
+// Далі - мінімальна підтримка Structured Field Values (RFC 8941),
+// достатня для заголовків Signature-Input, Signature та Content-Digest.
+
+func sfString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func parseSFString(raw string) (string, error) {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return "", fmt.Errorf("invalid structured field string: %s", raw)
+	}
+	var out strings.Builder
+	for i := 1; i < len(raw)-1; i++ {
+		c := raw[i]
+		if c == '\\' {
+			i++
+			if i >= len(raw)-1 || (raw[i] != '"' && raw[i] != '\\') {
+				return "", fmt.Errorf("invalid escape in structured field string: %s", raw)
+			}
+			c = raw[i]
+		} else if c == '"' {
+			return "", fmt.Errorf("invalid structured field string: %s", raw)
+		}
+		out.WriteByte(c)
+	}
+	return out.String(), nil
+}
+
+func parseSFByteSequence(raw string) ([]byte, error) {
+	if len(raw) < 2 || raw[0] != ':' || raw[len(raw)-1] != ':' {
+		return nil, fmt.Errorf("invalid structured field byte sequence: %s", raw)
+	}
+	return base64.StdEncoding.DecodeString(raw[1 : len(raw)-1])
+}
+
+type sfMember struct {
+	key   string
+	value string
+}
+
+// Розбиття словника на члени з урахуванням лапок та дужок
+func parseSFDictionary(header string) ([]sfMember, error) {
+	var (
+		members []sfMember
+		start   int
+		depth   int
+		quoted  bool
+	)
+	appendMember := func(raw string) error {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return nil
+		}
+		eq := strings.IndexByte(raw, '=')
+		if eq <= 0 {
+			return fmt.Errorf("invalid structured field dictionary member: %s", raw)
+		}
+		members = append(members, sfMember{key: strings.TrimSpace(raw[:eq]), value: strings.TrimSpace(raw[eq+1:])})
+		return nil
+	}
+	for i := 0; i < len(header); i++ {
+		switch c := header[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			if err := appendMember(header[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if quoted || depth != 0 {
+		return nil, fmt.Errorf("unbalanced structured field dictionary: %s", header)
+	}
+	if err := appendMember(header[start:]); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func sfLookup(members []sfMember, key string) (string, bool) {
+	for _, member := range members {
+		if member.key == key {
+			return member.value, true
+		}
+	}
+	return "", false
+}
+
+// Функція для розбору значення Signature-Input: ("a" "b");created=1;keyid="k"
+func parseSignatureInput(raw string) (*httpSigParams, error) {
+	if !strings.HasPrefix(raw, "(") {
+		return nil, fmt.Errorf("signature input must be an inner list: %s", raw)
+	}
+	sigParams := &httpSigParams{}
+	i := 1
+	for {
+		for i < len(raw) && raw[i] == ' ' {
+			i++
+		}
+		if i >= len(raw) {
+			return nil, fmt.Errorf("unterminated inner list: %s", raw)
+		}
+		if raw[i] == ')' {
+			i++
+			break
+		}
+		end, err := sfStringEnd(raw, i)
+		if err != nil {
+			return nil, err
+		}
+		component, err := parseSFString(raw[i:end])
+		if err != nil {
+			return nil, err
+		}
+		if end < len(raw) && raw[end] == ';' {
+			return nil, fmt.Errorf("component parameters are not supported: %s", component)
+		}
+		sigParams.components = append(sigParams.components, component)
+		i = end
+	}
+	for i < len(raw) {
+		if raw[i] != ';' {
+			return nil, fmt.Errorf("invalid signature parameters: %s", raw[i:])
+		}
+		i++
+		eq := strings.IndexByte(raw[i:], '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid signature parameters: %s", raw[i:])
+		}
+		key := raw[i : i+eq]
+		i += eq + 1
+		end := strings.IndexByte(raw[i:], ';') + i
+		if end < i {
+			end = len(raw)
+		}
+		if i < len(raw) && raw[i] == '"' {
+			var err error
+			if end, err = sfStringEnd(raw, i); err != nil {
+				return nil, err
+			}
+			if _, err = parseSFString(raw[i:end]); err != nil {
+				return nil, err
+			}
+		}
+		sigParams.add(key, raw[i:end])
+		i = end
+	}
+	return sigParams, nil
+}
+
+// Пошук кінця рядка у лапках, що починається з позиції start
+func sfStringEnd(raw string, start int) (int, error) {
+	if raw[start] != '"' {
+		return 0, fmt.Errorf("expected structured field string at: %s", raw[start:])
+	}
+	for i := start + 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated structured field string: %s", raw[start:])
+}
@@ -0,0 +1,163 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Декоратор Sign з метриками та трасуванням. Для кожного виклику:
+//
+//	turbo_signer_operations_total{algorithm,key_id,operation,result}  - лічильник
+//	turbo_signer_operation_duration_seconds{algorithm,key_id,operation} - гістограма
+//	спан turbo_signer.<operation> з атрибутами signer.algorithm, signer.key_id, signer.result
+//
+// result - ok або причина невдачі: error, empty_signature, invalid, missing_signature.
+
+const (
+	MetricOperations        = "turbo_signer_operations_total"
+	MetricOperationDuration = "turbo_signer_operation_duration_seconds"
+)
+
+const (
+	ResultOK               = "ok"
+	ResultError            = "error"
+	ResultEmptySignature   = "empty_signature"
+	ResultInvalid          = "invalid"
+	ResultMissingSignature = "missing_signature"
+)
+
+// Помилка для порожнього підпису, яким Sign повідомляє про невдачу
+var ErrEmptySignature = errors.New("signer returned empty signature")
+
+type (
+	InstrumentOptions struct {
+		Metrics Metrics // nil - без метрик
+		Tracer  Tracer  // nil - без спанів
+		KeyID   string  // порожній - API ключ підписувача
+	}
+	SignInstrumented struct {
+		sign      Sign
+		options   InstrumentOptions
+		algorithm string
+		ctx       context.Context
+		now       func() time.Time
+	}
+)
+
+func NewSignInstrumented(sign Sign, options InstrumentOptions) *SignInstrumented {
+	if options.KeyID == "" {
+		options.KeyID = sign.GetAPIKey()
+	}
+	return &SignInstrumented{
+		sign:      sign,
+		options:   options,
+		algorithm: Algorithm(sign),
+		ctx:       context.Background(),
+		now:       time.Now,
+	}
+}
+
+// Функція для отримання декоратора, спани якого є дочірніми до спану з ctx
+func (sign *SignInstrumented) WithContext(ctx context.Context) *SignInstrumented {
+	clone := *sign
+	clone.ctx = ctx
+	return &clone
+}
+
+// Функція для заміни годинника, яким вимірюється тривалість операцій.
+// Викликається до початку використання декоратора.
+func (sign *SignInstrumented) SetClock(now func() time.Time) {
+	sign.now = now
+}
+
+func (sign *SignInstrumented) CreateSignature(queryString string) (signature string) {
+	_, finish := sign.start(sign.ctx, "CreateSignature")
+	signature = sign.sign.CreateSignature(queryString)
+	if signature == "" {
+		finish(ResultEmptySignature, ErrEmptySignature)
+	} else {
+		finish(ResultOK, nil)
+	}
+	return
+}
+
+// Функція для створення підпису з помилкою, операція у метриках - CreateSignature.
+// Спан дочірній до спану з ctx, а не з WithContext, і передається обгорнутому Sign.
+func (sign *SignInstrumented) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	ctx, finish := sign.start(ctx, "CreateSignature")
+	signature, err := createSignature(ctx, sign.sign, queryString)
+	switch {
+	case errors.Is(err, ErrEmptySignature):
+		finish(ResultEmptySignature, err)
+	case err != nil:
+		finish(ResultError, err)
+	default:
+		finish(ResultOK, nil)
+	}
+	return signature, err
+}
+
+func (sign *SignInstrumented) ValidateSignature(message, signature string) bool {
+	_, finish := sign.start(sign.ctx, "ValidateSignature")
+	if signature == "" {
+		finish(ResultMissingSignature, nil)
+		return false
+	}
+	valid := sign.sign.ValidateSignature(message, signature)
+	sign.finishValidation(finish, valid)
+	return valid
+}
+
+func (sign *SignInstrumented) GetAPIKey() string {
+	return sign.sign.GetAPIKey()
+}
+
+func (sign *SignInstrumented) finishValidation(finish func(string, error), valid bool) {
+	if valid {
+		finish(ResultOK, nil)
+	} else {
+		finish(ResultInvalid, nil)
+	}
+}
+
+// Початок вимірювання операції: спан дочірній до спану з ctx, повертається ctx
+// зі спаном операції для обгорнутого Sign та функція завершення з результатом
+func (sign *SignInstrumented) start(ctx context.Context, operation string) (context.Context, func(result string, err error)) {
+	started := sign.now()
+	var span Span
+	if sign.options.Tracer != nil {
+		ctx, span = sign.options.Tracer.Start(ctx, "turbo_signer."+operation)
+		span.SetAttributes(
+			Label{"signer.algorithm", sign.algorithm},
+			Label{"signer.key_id", sign.options.KeyID},
+			Label{"signer.operation", operation},
+		)
+	}
+	return ctx, func(result string, err error) {
+		if metrics := sign.options.Metrics; metrics != nil {
+			metrics.IncCounter(MetricOperations,
+				Label{"algorithm", sign.algorithm},
+				Label{"key_id", sign.options.KeyID},
+				Label{"operation", operation},
+				Label{"result", result})
+			metrics.ObserveHistogram(MetricOperationDuration, sign.now().Sub(started).Seconds(),
+				Label{"algorithm", sign.algorithm},
+				Label{"key_id", sign.options.KeyID},
+				Label{"operation", operation})
+		}
+		if span == nil {
+			return
+		}
+		span.SetAttributes(Label{"signer.result", result})
+		if err != nil {
+			span.RecordError(err)
+		}
+		if result == ResultOK {
+			span.SetStatus(SpanStatusOK, "")
+		} else {
+			span.SetStatus(SpanStatusError, result)
+		}
+		span.End()
+	}
+}
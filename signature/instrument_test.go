@@ -0,0 +1,153 @@
+package signature_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func operationLabels(operation, result string) []signature.Label {
+	return []signature.Label{
+		{Name: "algorithm", Value: "hmac-sha256"},
+		{Name: "key_id", Value: "hmac-main"},
+		{Name: "operation", Value: operation},
+		{Name: "result", Value: result},
+	}
+}
+
+// Test 1: Counters per operation and result
+func TestSignInstrumentedMetrics(t *testing.T) {
+	registry := signature.NewPrometheusRegistry(nil)
+	sign := signature.NewSignInstrumented(signature.NewSignHMAC("apy_key", "apy_secret"), signature.InstrumentOptions{
+		Metrics: registry,
+		KeyID:   "hmac-main",
+	})
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.False(t, sign.ValidateSignatureParams(params))
+	signed.Set("timestamp", 1)
+	assert.False(t, sign.ValidateSignatureParams(signed))
+	message := "timestamp=1610612740000"
+	assert.True(t, sign.ValidateSignature(message, sign.CreateSignature(message)))
+	assert.False(t, sign.ValidateSignature(message, "wrong"))
+
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("SignParameters", signature.ResultOK)...))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("ValidateSignatureParams", signature.ResultOK)...))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("ValidateSignatureParams", signature.ResultMissingSignature)...))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("ValidateSignatureParams", signature.ResultInvalid)...))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("CreateSignature", signature.ResultOK)...))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("ValidateSignature", signature.ResultInvalid)...))
+
+	// Знищений ключ дає порожній підпис
+	hmacSign := signature.NewSignHMAC("apy_key", "apy_secret")
+	hmacSign.Destroy()
+	destroyed := signature.NewSignInstrumented(hmacSign, signature.InstrumentOptions{Metrics: registry, KeyID: "hmac-main"})
+	assert.Empty(t, destroyed.CreateSignature(message))
+	assert.Equal(t, 1.0, registry.Counter(signature.MetricOperations, operationLabels("CreateSignature", signature.ResultEmptySignature)...))
+}
+
+// Test 2: Prometheus text exposition
+func TestPrometheusRegistry(t *testing.T) {
+	registry := signature.NewPrometheusRegistry([]float64{0.5, 0.1})
+	registry.IncCounter("requests_total", signature.Label{Name: "path", Value: `a"b`})
+	registry.IncCounter("requests_total", signature.Label{Name: "path", Value: `a"b`})
+	registry.ObserveHistogram("latency_seconds", 0.05)
+	registry.ObserveHistogram("latency_seconds", 0.3)
+	registry.ObserveHistogram("latency_seconds", 2)
+	var out bytes.Buffer
+	_, err := registry.WriteTo(&out)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"# TYPE requests_total counter",
+		`requests_total{path="a\"b"} 2`,
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{le="0.1"} 1`,
+		`latency_seconds_bucket{le="0.5"} 2`,
+		`latency_seconds_bucket{le="+Inf"} 3`,
+		"latency_seconds_sum 2.35",
+		"latency_seconds_count 3",
+		"",
+	}, "\n"), out.String())
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, out.String(), recorder.Body.String())
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+}
+
+// Test 3: Spans are recorded with attributes and status
+func TestSignInstrumentedSpans(t *testing.T) {
+	tracer := signature.NewMemoryTracer()
+	registry := signature.NewPrometheusRegistry(nil)
+	sign := signature.NewSignInstrumented(signature.NewSignHMAC("apy_key", "apy_secret"), signature.InstrumentOptions{
+		Metrics: registry,
+		Tracer:  tracer,
+	}).WithContext(context.Background())
+	sign.CreateSignature("timestamp=1610612740000")
+	_, err := sign.SignParameters(nil)
+	assert.NotNil(t, err)
+	sign.ValidateSignature("timestamp=1610612740000", "")
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "turbo_signer.CreateSignature", spans[0].Name)
+	assert.Equal(t, signature.SpanStatusOK, spans[0].Status)
+	assert.Contains(t, spans[0].Attributes, signature.Label{Name: "signer.key_id", Value: "apy_key"})
+	assert.Contains(t, spans[0].Attributes, signature.Label{Name: "signer.algorithm", Value: "hmac-sha256"})
+	assert.Equal(t, signature.SpanStatusError, spans[1].Status)
+	assert.Len(t, spans[1].Errors, 1)
+	assert.Contains(t, spans[2].Attributes, signature.Label{Name: "signer.result", Value: signature.ResultMissingSignature})
+	assert.False(t, spans[2].End.Before(spans[2].Start))
+
+	var out bytes.Buffer
+	registry.WriteTo(&out)
+	assert.Contains(t, out.String(), `turbo_signer_operation_duration_seconds_count{algorithm="hmac-sha256",key_id="apy_key",operation="CreateSignature"} 1`)
+}
+
+type traceKey struct{}
+
+// Tracer, що записує батьківський спан з ctx і кладе у ctx свій
+type parentTracer struct {
+	*signature.MemoryTracer
+	parents []any
+}
+
+func (tracer *parentTracer) Start(ctx context.Context, name string) (context.Context, signature.Span) {
+	tracer.parents = append(tracer.parents, ctx.Value(traceKey{}))
+	_, span := tracer.MemoryTracer.Start(ctx, name)
+	return context.WithValue(ctx, traceKey{}, name), span
+}
+
+// Sign, що запам'ятовує спан з ctx виклику
+type traceSigner struct {
+	*signature.SignHMAC
+	span any
+}
+
+func (sign *traceSigner) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	sign.span = ctx.Value(traceKey{})
+	return sign.SignHMAC.CreateSignatureContext(ctx, queryString)
+}
+
+// Test 4: CreateSignatureContext spans are children of the span in ctx
+func TestSignInstrumentedContextParent(t *testing.T) {
+	tracer := &parentTracer{MemoryTracer: signature.NewMemoryTracer()}
+	inner := &traceSigner{SignHMAC: signature.NewSignHMAC("apy_key", "apy_secret")}
+	sign := signature.NewSignInstrumented(inner, signature.InstrumentOptions{Tracer: tracer}).
+		WithContext(context.WithValue(context.Background(), traceKey{}, "decorator"))
+	_, err := sign.CreateSignatureContext(context.WithValue(context.Background(), traceKey{}, "request"), "timestamp=1610612740000")
+	assert.Nil(t, err)
+	sign.CreateSignature("timestamp=1610612740000")
+	// Спан виклику - з ctx аргументу, обгорнутий Sign отримує ctx зі спаном операції
+	assert.Equal(t, []any{"request", "decorator"}, tracer.parents)
+	assert.Equal(t, "turbo_signer.CreateSignature", inner.span)
+}
@@ -0,0 +1,219 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Набір ключів з ідентифікаторами та періодами дії.
+// Підпис створюється поточним основним (primary) ключем,
+// перевірка виконується будь-яким ключем, що діє на поточний момент.
+// Ротація: AddKey -> Promote -> Retire без перезапуску сервісу.
+
+var (
+	ErrKeyNotFound     = errors.New("key not found in keyring")
+	ErrKeyExists       = errors.New("key already exists in keyring")
+	ErrKeyNotValid     = errors.New("key is not valid at this time")
+	ErrKeyIsPrimary    = errors.New("primary key can not be retired")
+	ErrKeyringEmptyKey = errors.New("keyring key must have id and signer")
+)
+
+type (
+	KeyringKey struct {
+		ID        string
+		Sign      Sign
+		NotBefore time.Time // якщо нульовий, ключ діє одразу
+		NotAfter  time.Time // якщо нульовий, ключ не має терміну дії
+	}
+	SignKeyring struct {
+		mutex   sync.RWMutex
+		keys    []KeyringKey
+		primary int
+		now     func() time.Time
+	}
+)
+
+// Функція перевірки, чи діє ключ на момент at
+func (key KeyringKey) ValidAt(at time.Time) bool {
+	if !key.NotBefore.IsZero() && at.Before(key.NotBefore) {
+		return false
+	}
+	if !key.NotAfter.IsZero() && !at.Before(key.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// primary - ключ, яким одразу підписуються запити, others - додаткові ключі для перевірки
+func NewSignKeyring(primary KeyringKey, others ...KeyringKey) (*SignKeyring, error) {
+	keyring := &SignKeyring{now: time.Now}
+	if err := keyring.AddKey(primary); err != nil {
+		return nil, err
+	}
+	for _, key := range others {
+		if err := keyring.AddKey(key); err != nil {
+			return nil, err
+		}
+	}
+	if err := keyring.Promote(primary.ID); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// Функція для заміни годинника, за яким перевіряється період дії ключів
+func (keyring *SignKeyring) SetClock(now func() time.Time) {
+	keyring.mutex.Lock()
+	defer keyring.mutex.Unlock()
+	keyring.now = now
+}
+
+// Функція для створення підпису основним ключем, поза періодом його дії - порожній підпис
+func (keyring *SignKeyring) CreateSignature(queryString string) string {
+	key, err := keyring.signingKey()
+	if err != nil {
+		return ""
+	}
+	return key.Sign.CreateSignature(queryString)
+}
+
+// Функція для створення підпису з помилкою: ErrKeyNotValid поза періодом дії основного ключа
+// або помилка самого ключа (напр. ErrSignerDestroyed)
+func (keyring *SignKeyring) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	key, err := keyring.signingKey()
+	if err != nil {
+		return "", err
+	}
+	return createSignature(ctx, key.Sign, queryString)
+}
+
+func (keyring *SignKeyring) ValidateSignature(message, signature string) bool {
+	for _, key := range keyring.validKeys() {
+		if key.Sign.ValidateSignature(message, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// API ключ основного ключа, поза періодом його дії - порожній рядок
+func (keyring *SignKeyring) GetAPIKey() string {
+	key, err := keyring.signingKey()
+	if err != nil {
+		return ""
+	}
+	return key.Sign.GetAPIKey()
+}
+
+// Функція для отримання поточного основного ключа
+func (keyring *SignKeyring) Primary() KeyringKey {
+	keyring.mutex.RLock()
+	defer keyring.mutex.RUnlock()
+	return keyring.keys[keyring.primary]
+}
+
+// Основний ключ для підпису: ключ, строк дії якого минув, не підписує,
+// доки основним не призначено інший чинний ключ
+func (keyring *SignKeyring) signingKey() (KeyringKey, error) {
+	keyring.mutex.RLock()
+	defer keyring.mutex.RUnlock()
+	key := keyring.keys[keyring.primary]
+	if !key.ValidAt(keyring.now()) {
+		return KeyringKey{}, fmt.Errorf("%w: %s", ErrKeyNotValid, key.ID)
+	}
+	return key, nil
+}
+
+// Функція для отримання ключа за ідентифікатором
+func (keyring *SignKeyring) Key(id string) (KeyringKey, error) {
+	keyring.mutex.RLock()
+	defer keyring.mutex.RUnlock()
+	index, err := keyring.indexOf(id)
+	if err != nil {
+		return KeyringKey{}, err
+	}
+	return keyring.keys[index], nil
+}
+
+// Функція для отримання ідентифікаторів усіх ключів у порядку додавання
+func (keyring *SignKeyring) KeyIDs() []string {
+	keyring.mutex.RLock()
+	defer keyring.mutex.RUnlock()
+	ids := make([]string, len(keyring.keys))
+	for i, key := range keyring.keys {
+		ids[i] = key.ID
+	}
+	return ids
+}
+
+// Крок 1 ротації: додавання нового ключа, який поки що лише перевіряє підписи
+func (keyring *SignKeyring) AddKey(key KeyringKey) error {
+	if key.ID == "" || key.Sign == nil {
+		return ErrKeyringEmptyKey
+	}
+	keyring.mutex.Lock()
+	defer keyring.mutex.Unlock()
+	if _, err := keyring.indexOf(key.ID); err == nil {
+		return fmt.Errorf("%w: %s", ErrKeyExists, key.ID)
+	}
+	keyring.keys = append(keyring.keys, key)
+	return nil
+}
+
+// Крок 2 ротації: призначення ключа основним для підпису
+func (keyring *SignKeyring) Promote(id string) error {
+	keyring.mutex.Lock()
+	defer keyring.mutex.Unlock()
+	index, err := keyring.indexOf(id)
+	if err != nil {
+		return err
+	}
+	if !keyring.keys[index].ValidAt(keyring.now()) {
+		return fmt.Errorf("%w: %s", ErrKeyNotValid, id)
+	}
+	keyring.primary = index
+	return nil
+}
+
+// Крок 3 ротації: вилучення старого ключа, підписи ним більше не приймаються
+func (keyring *SignKeyring) Retire(id string) error {
+	keyring.mutex.Lock()
+	defer keyring.mutex.Unlock()
+	index, err := keyring.indexOf(id)
+	if err != nil {
+		return err
+	}
+	if index == keyring.primary {
+		return fmt.Errorf("%w: %s", ErrKeyIsPrimary, id)
+	}
+	keyring.keys = append(keyring.keys[:index], keyring.keys[index+1:]...)
+	if index < keyring.primary {
+		keyring.primary--
+	}
+	return nil
+}
+
+func (keyring *SignKeyring) indexOf(id string) (int, error) {
+	for i, key := range keyring.keys {
+		if key.ID == id {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+}
+
+func (keyring *SignKeyring) validKeys() []KeyringKey {
+	keyring.mutex.RLock()
+	defer keyring.mutex.RUnlock()
+	now := keyring.now()
+	keys := make([]KeyringKey, 0, len(keyring.keys))
+	for _, key := range keyring.keys {
+		if key.ValidAt(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
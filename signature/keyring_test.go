@@ -0,0 +1,115 @@
+package signature_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test 1: Sign with primary key, validate with any valid key
+func TestKeyringSignAndValidate(t *testing.T) {
+	oldKey := signature.NewSignHMAC("old_key", "old_secret")
+	newKey := signature.NewSignHMAC("new_key", "new_secret")
+	keyring, err := signature.NewSignKeyring(
+		signature.KeyringKey{ID: "old", Sign: oldKey},
+		signature.KeyringKey{ID: "new", Sign: newKey})
+	assert.Nil(t, err)
+	assert.Equal(t, "old", keyring.Primary().ID)
+	assert.Equal(t, "old_key", keyring.GetAPIKey())
+
+	message := "timestamp=1610612740000"
+	assert.Equal(t, oldKey.CreateSignature(message), keyring.CreateSignature(message))
+	assert.True(t, keyring.ValidateSignature(message, newKey.CreateSignature(message)))
+	assert.False(t, keyring.ValidateSignature(message, "wrong_signature"))
+
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	params, err = keyring.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, keyring.ValidateSignatureParams(params))
+	assert.True(t, oldKey.ValidateSignatureParams(params))
+}
+
+// Test 2: Staged rotation - add, promote, retire
+func TestKeyringRotation(t *testing.T) {
+	oldKey := signature.NewSignHMAC("old_key", "old_secret")
+	newKey := signature.NewSignHMAC("new_key", "new_secret")
+	keyring, err := signature.NewSignKeyring(signature.KeyringKey{ID: "old", Sign: oldKey})
+	assert.Nil(t, err)
+	message := "timestamp=1610612740000"
+	oldSignature := keyring.CreateSignature(message)
+
+	// Додавання
+	assert.Nil(t, keyring.AddKey(signature.KeyringKey{ID: "new", Sign: newKey}))
+	assert.ErrorIs(t, keyring.AddKey(signature.KeyringKey{ID: "new", Sign: newKey}), signature.ErrKeyExists)
+	assert.Equal(t, oldSignature, keyring.CreateSignature(message))
+
+	// Призначення основним
+	assert.Nil(t, keyring.Promote("new"))
+	assert.Equal(t, "new_key", keyring.GetAPIKey())
+	assert.Equal(t, newKey.CreateSignature(message), keyring.CreateSignature(message))
+	assert.True(t, keyring.ValidateSignature(message, oldSignature))
+
+	// Вилучення
+	assert.ErrorIs(t, keyring.Retire("new"), signature.ErrKeyIsPrimary)
+	assert.Nil(t, keyring.Retire("old"))
+	assert.ErrorIs(t, keyring.Retire("old"), signature.ErrKeyNotFound)
+	assert.False(t, keyring.ValidateSignature(message, oldSignature))
+	assert.Equal(t, []string{"new"}, keyring.KeyIDs())
+	assert.Equal(t, "new", keyring.Primary().ID)
+}
+
+// Test 3: Validity windows
+func TestKeyringValidityWindow(t *testing.T) {
+	current := signature.NewSignHMAC("current_key", "current_secret")
+	expired := signature.NewSignHMAC("expired_key", "expired_secret")
+	future := signature.NewSignHMAC("future_key", "future_secret")
+	keyring, err := signature.NewSignKeyring(
+		signature.KeyringKey{ID: "current", Sign: current},
+		signature.KeyringKey{ID: "expired", Sign: expired, NotAfter: time.Now().Add(-time.Hour)},
+		signature.KeyringKey{ID: "future", Sign: future, NotBefore: time.Now().Add(time.Hour)})
+	assert.Nil(t, err)
+
+	message := "timestamp=1610612740000"
+	assert.False(t, keyring.ValidateSignature(message, expired.CreateSignature(message)))
+	assert.False(t, keyring.ValidateSignature(message, future.CreateSignature(message)))
+	assert.ErrorIs(t, keyring.Promote("expired"), signature.ErrKeyNotValid)
+	assert.ErrorIs(t, keyring.Promote("future"), signature.ErrKeyNotValid)
+	assert.Equal(t, "current", keyring.Primary().ID)
+}
+
+// Test 4: Primary key expires - no signing until another valid key is promoted
+func TestKeyringPrimaryExpires(t *testing.T) {
+	// NewSignKeyring перевіряє основний ключ за системним годинником
+	clock := signaturetest.NewClock(time.Now())
+	oldKey := signature.NewSignHMAC("old_key", "old_secret")
+	newKey := signature.NewSignHMAC("new_key", "new_secret")
+	keyring, err := signature.NewSignKeyring(
+		signature.KeyringKey{ID: "old", Sign: oldKey, NotAfter: clock.Now().Add(time.Hour)},
+		signature.KeyringKey{ID: "new", Sign: newKey})
+	assert.Nil(t, err)
+	keyring.SetClock(clock.Now)
+
+	message := "timestamp=1610612740000"
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	assert.Equal(t, oldKey.CreateSignature(message), keyring.CreateSignature(message))
+
+	clock.Advance(time.Hour)
+	assert.Empty(t, keyring.CreateSignature(message))
+	assert.Empty(t, keyring.GetAPIKey())
+	signed, err := keyring.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrKeyNotValid)
+	assert.Nil(t, signed)
+	assert.Equal(t, "old", keyring.Primary().ID)
+
+	assert.Nil(t, keyring.Promote("new"))
+	assert.Equal(t, "new_key", keyring.GetAPIKey())
+	signed, err = keyring.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, newKey.ValidateSignatureParams(signed))
+}
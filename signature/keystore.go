@@ -0,0 +1,304 @@
+package signature
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Сховище ключів: за іменем облікових даних повертає готовий Sign.
+// Бекенди: змінні оточення, каталог змонтованих секретів,
+// зашифрований локальний файл ключів та сховище в пам'яті для тестів.
+
+const (
+	CredentialHMAC    = "hmac"
+	CredentialRSA     = "rsa"
+	CredentialEd25519 = "ed25519"
+)
+
+var (
+	ErrCredentialNotFound = errors.New("credential not found")
+	ErrCredentialType     = errors.New("unknown credential type")
+	ErrKeyFileDecrypt     = errors.New("failed to decrypt key file")
+	ErrKeyFileParams      = errors.New("unsupported key file scrypt parameters")
+)
+
+// Межі параметрів scrypt з файлу ключів, щоб підроблений файл не змусив процес
+// виділити гігабайти пам'яті (128·N·r байт) чи обчислювати ключ годинами
+const (
+	maxKeyFileScryptN      = 1 << 20
+	maxKeyFileScryptRP     = 1 << 6
+	maxKeyFileScryptMemory = 1 << 30
+)
+
+type (
+	KeyStore interface {
+		Signer(name string) (Sign, error)
+	}
+	Credential struct {
+		Type       string `json:"type"`
+		APIKey     string `json:"api_key"`
+		SecretKey  string `json:"secret_key,omitempty"`
+		PublicKey  string `json:"public_key,omitempty"`
+		PrivateKey string `json:"private_key,omitempty"`
+	}
+	// Змінні оточення <Prefix><NAME>_TYPE, _API_KEY, _SECRET_KEY, _PUBLIC_KEY, _PRIVATE_KEY
+	EnvKeyStore struct {
+		Prefix string
+	}
+	// Каталог <Dir>/<name>/ з файлами type, api_key, secret_key, public_key.pem, private_key.pem
+	DirKeyStore struct {
+		Dir string
+	}
+	EncryptedFileKeyStore struct {
+		credentials map[string]Credential
+	}
+	MemoryKeyStore struct {
+		mutex   sync.RWMutex
+		signers map[string]Sign
+	}
+	credentialJSON   Credential
+	encryptedKeyFile struct {
+		KDF        string `json:"kdf"`
+		N          int    `json:"n"`
+		R          int    `json:"r"`
+		P          int    `json:"p"`
+		Salt       []byte `json:"salt"`
+		Nonce      []byte `json:"nonce"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+)
+
+// Функція для створення Sign з облікових даних
+func (credential Credential) Signer() (Sign, error) {
+	switch credential.Type {
+	case CredentialHMAC:
+		return NewSignHMAC(PublicKey(credential.APIKey), SecretKey(credential.SecretKey)), nil
+	case CredentialRSA:
+		return NewSignRSA(credential.APIKey, credential.PublicKey, credential.PrivateKey)
+	case CredentialEd25519:
+		return NewSignEd25519(credential.APIKey, credential.PublicKey, credential.PrivateKey)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrCredentialType, credential.Type)
+}
+
+func NewEnvKeyStore(prefix string) *EnvKeyStore {
+	return &EnvKeyStore{Prefix: prefix}
+}
+
+func (store *EnvKeyStore) Signer(name string) (Sign, error) {
+	prefix := store.Prefix + envName(name) + "_"
+	credentialType, ok := os.LookupEnv(prefix + "TYPE")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, name)
+	}
+	return Credential{
+		Type:       strings.ToLower(strings.TrimSpace(credentialType)),
+		APIKey:     os.Getenv(prefix + "API_KEY"),
+		SecretKey:  os.Getenv(prefix + "SECRET_KEY"),
+		PublicKey:  os.Getenv(prefix + "PUBLIC_KEY"),
+		PrivateKey: os.Getenv(prefix + "PRIVATE_KEY"),
+	}.Signer()
+}
+
+// Перетворення імені на частину імені змінної оточення: binance-spot -> BINANCE_SPOT
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func NewDirKeyStore(dir string) *DirKeyStore {
+	return &DirKeyStore{Dir: dir}
+}
+
+func (store *DirKeyStore) Signer(name string) (Sign, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return nil, fmt.Errorf("%w: invalid name %q", ErrCredentialNotFound, name)
+	}
+	dir := filepath.Join(store.Dir, name)
+	credentialType, err := readSecretFile(dir, "type", true)
+	if err != nil {
+		return nil, err
+	}
+	credentialType = strings.TrimSpace(credentialType)
+	if credentialType == "" {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, name)
+	}
+	credential := Credential{Type: strings.ToLower(credentialType)}
+	if credential.APIKey, err = readSecretFile(dir, "api_key", true); err != nil {
+		return nil, err
+	}
+	if credential.SecretKey, err = readSecretFile(dir, "secret_key", true); err != nil {
+		return nil, err
+	}
+	if credential.PublicKey, err = readSecretFile(dir, "public_key.pem", false); err != nil {
+		return nil, err
+	}
+	if credential.PrivateKey, err = readSecretFile(dir, "private_key.pem", false); err != nil {
+		return nil, err
+	}
+	return credential.Signer()
+}
+
+// Функція для читання файлу секрету, відсутній файл дає порожнє значення
+func readSecretFile(dir, file string, trim bool) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, file))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file %s: %v", file, err)
+	}
+	if trim {
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	return string(content), nil
+}
+
+// Функція для відкриття зашифрованого файлу ключів, створеного WriteEncryptedKeyFile
+func NewEncryptedFileKeyStore(path string, passphrase []byte) (*EncryptedFileKeyStore, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %v", err)
+	}
+	var file encryptedKeyFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("error parsing key file: %v", err)
+	}
+	if file.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key file kdf: %q", file.KDF)
+	}
+	if err := checkScryptParams(file.N, file.R, file.P); err != nil {
+		return nil, err
+	}
+	aead, err := keyFileCipher(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, ErrKeyFileDecrypt
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(file.KDF))
+	if err != nil {
+		return nil, ErrKeyFileDecrypt
+	}
+	store := &EncryptedFileKeyStore{}
+	if err := json.Unmarshal(plaintext, &store.credentials); err != nil {
+		return nil, fmt.Errorf("error parsing key file credentials: %v", err)
+	}
+	return store, nil
+}
+
+func (store *EncryptedFileKeyStore) Signer(name string) (Sign, error) {
+	credential, ok := store.credentials[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, name)
+	}
+	return credential.Signer()
+}
+
+// Функція для запису облікових даних у файл, зашифрований AES-256-GCM з ключем зі scrypt
+func WriteEncryptedKeyFile(path string, passphrase []byte, credentials map[string]Credential) error {
+	// Credential маскує секрети у MarshalJSON, тому серіалізуємо через тип без методів
+	plain := make(map[string]credentialJSON, len(credentials))
+	for name, credential := range credentials {
+		plain[name] = credentialJSON(credential)
+	}
+	plaintext, err := json.Marshal(plain)
+	if err != nil {
+		return fmt.Errorf("error marshalling credentials: %v", err)
+	}
+	file := encryptedKeyFile{KDF: "scrypt", N: 1 << 15, R: 8, P: 1, Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	aead, err := keyFileCipher(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, []byte(file.KDF))
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling key file: %v", err)
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+// N - степінь двійки не більше maxKeyFileScryptN, r·p та пам'ять обмежені
+func checkScryptParams(n, r, p int) error {
+	if n < 2 || n > maxKeyFileScryptN || n&(n-1) != 0 ||
+		r < 1 || p < 1 || r > maxKeyFileScryptRP || p > maxKeyFileScryptRP || r*p > maxKeyFileScryptRP ||
+		128*n*r > maxKeyFileScryptMemory {
+		return fmt.Errorf("%w: N=%d r=%d p=%d", ErrKeyFileParams, n, r, p)
+	}
+	return nil
+}
+
+func keyFileCipher(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving key file key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{signers: make(map[string]Sign)}
+}
+
+// Функція для додавання облікових даних, Sign створюється одразу
+func (store *MemoryKeyStore) Put(name string, credential Credential) error {
+	sign, err := credential.Signer()
+	if err != nil {
+		return err
+	}
+	store.PutSigner(name, sign)
+	return nil
+}
+
+// Функція для додавання готового Sign, наприклад підробленого у тестах
+func (store *MemoryKeyStore) PutSigner(name string, sign Sign) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.signers[name] = sign
+}
+
+func (store *MemoryKeyStore) Delete(name string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.signers, name)
+}
+
+func (store *MemoryKeyStore) Signer(name string) (Sign, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	sign, ok := store.signers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, name)
+	}
+	return sign, nil
+}
@@ -0,0 +1,124 @@
+package signature_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+const keyStoreMessage = "timestamp=1610612740000"
+
+// Test 1: Environment variables key store
+func TestEnvKeyStore(t *testing.T) {
+	t.Setenv("TURBO_BINANCE_SPOT_TYPE", "hmac")
+	t.Setenv("TURBO_BINANCE_SPOT_API_KEY", "apy_key")
+	t.Setenv("TURBO_BINANCE_SPOT_SECRET_KEY", "apy_secret")
+	t.Setenv("TURBO_OKX_TYPE", "ed25519")
+	t.Setenv("TURBO_OKX_API_KEY", "okx_key")
+	t.Setenv("TURBO_OKX_PUBLIC_KEY", rfc9421Ed25519PublicKey)
+	t.Setenv("TURBO_OKX_PRIVATE_KEY", rfc9421Ed25519PrivateKey)
+	store := signature.NewEnvKeyStore("TURBO_")
+
+	sign, err := store.Signer("binance-spot")
+	assert.Nil(t, err)
+	assert.Equal(t, "apy_key", sign.GetAPIKey())
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", sign.CreateSignature(keyStoreMessage))
+
+	sign, err = store.Signer("okx")
+	assert.Nil(t, err)
+	assert.True(t, sign.ValidateSignature(keyStoreMessage, sign.CreateSignature(keyStoreMessage)))
+
+	_, err = store.Signer("bybit")
+	assert.ErrorIs(t, err, signature.ErrCredentialNotFound)
+}
+
+// Test 2: Directory of mounted secret files
+func TestDirKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "rsa"), 0o700))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "rsa", "type"), []byte(" RSA \r\n"), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "rsa", "api_key"), []byte("apy_key\n"), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "rsa", "public_key.pem"), []byte(rsaTestPublicKey), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "rsa", "private_key.pem"), []byte(rsaTestPrivateKey), 0o600))
+	store := signature.NewDirKeyStore(dir)
+
+	sign, err := store.Signer("rsa")
+	assert.Nil(t, err)
+	assert.Equal(t, "apy_key", sign.GetAPIKey())
+	assert.True(t, sign.ValidateSignature(keyStoreMessage, sign.CreateSignature(keyStoreMessage)))
+
+	_, err = store.Signer("missing")
+	assert.ErrorIs(t, err, signature.ErrCredentialNotFound)
+	_, err = store.Signer("../rsa")
+	assert.ErrorIs(t, err, signature.ErrCredentialNotFound)
+}
+
+// Test 3: Encrypted key file
+func TestEncryptedFileKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	err := signature.WriteEncryptedKeyFile(path, []byte("passphrase"), map[string]signature.Credential{
+		"binance": {Type: signature.CredentialHMAC, APIKey: "apy_key", SecretKey: "apy_secret"},
+	})
+	assert.Nil(t, err)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(content), "apy_secret")
+
+	store, err := signature.NewEncryptedFileKeyStore(path, []byte("passphrase"))
+	assert.Nil(t, err)
+	sign, err := store.Signer("binance")
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", sign.CreateSignature(keyStoreMessage))
+
+	_, err = signature.NewEncryptedFileKeyStore(path, []byte("wrong passphrase"))
+	assert.ErrorIs(t, err, signature.ErrKeyFileDecrypt)
+}
+
+// Test 4: In-memory key store
+func TestMemoryKeyStore(t *testing.T) {
+	store := signature.NewMemoryKeyStore()
+	assert.Nil(t, store.Put("binance", signature.Credential{Type: signature.CredentialHMAC, APIKey: "apy_key", SecretKey: "apy_secret"}))
+	assert.ErrorIs(t, store.Put("bad", signature.Credential{Type: "dsa"}), signature.ErrCredentialType)
+
+	var keyStore signature.KeyStore = store
+	sign, err := keyStore.Signer("binance")
+	assert.Nil(t, err)
+	assert.Equal(t, "apy_key", sign.GetAPIKey())
+
+	store.Delete("binance")
+	_, err = keyStore.Signer("binance")
+	assert.ErrorIs(t, err, signature.ErrCredentialNotFound)
+}
+
+// Test 5: Encrypted key file with scrypt parameters out of bounds
+func TestEncryptedFileKeyStoreParams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	err := signature.WriteEncryptedKeyFile(path, []byte("passphrase"), map[string]signature.Credential{
+		"binance": {Type: signature.CredentialHMAC, APIKey: "apy_key", SecretKey: "apy_secret"},
+	})
+	assert.Nil(t, err)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	for _, params := range []struct{ n, r, p int }{
+		{1 << 30, 8, 1}, // пам'ять та час
+		{1000, 8, 1},    // не степінь двійки
+		{0, 8, 1},
+		{1 << 15, 0, 1},
+		{1 << 15, 8, 1 << 20}, // r·p
+		{1 << 20, 16, 1},      // 2 GiB пам'яті
+	} {
+		var file map[string]any
+		assert.Nil(t, json.Unmarshal(content, &file))
+		file["n"], file["r"], file["p"] = params.n, params.r, params.p
+		tampered, err := json.Marshal(file)
+		assert.Nil(t, err)
+		assert.Nil(t, os.WriteFile(path, tampered, 0o600))
+		_, err = signature.NewEncryptedFileKeyStore(path, []byte("passphrase"))
+		assert.ErrorIs(t, err, signature.ErrKeyFileParams, "%+v", params)
+	}
+}
@@ -0,0 +1,250 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+const (
+	AWSSigningRSAPKCS1SHA256 = "RSASSA_PKCS1_V1_5_SHA_256"
+	AWSMacHMACSHA256         = "HMAC_SHA_256"
+)
+
+type (
+	AWSConfig struct {
+		Endpoint        string // напр. https://kms.eu-central-1.amazonaws.com
+		Region          string
+		AccessKeyID     string
+		SecretAccessKey string
+		SessionToken    string // необов'язковий
+		KeyID           string // ідентифікатор, ARN або alias ключа
+		// RSASSA_PKCS1_V1_5_SHA_256 (операції Sign/Verify) або HMAC_SHA_256 (GenerateMac/VerifyMac)
+		Algorithm  string
+		APIKey     string
+		HTTPClient *http.Client
+	}
+	// Підписувач AWS KMS (або сумісного API). Запити підписуються AWS Signature Version 4.
+	SignAWSKMS struct {
+		config    AWSConfig
+		now       func() time.Time
+		mutex     sync.RWMutex // облікові дані, годинник та ознака знищення
+		destroyed bool
+	}
+)
+
+func NewSignAWSKMS(config AWSConfig) (*SignAWSKMS, error) {
+	switch config.Algorithm {
+	case "":
+		config.Algorithm = AWSSigningRSAPKCS1SHA256
+	case AWSSigningRSAPKCS1SHA256, AWSMacHMACSHA256:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrKeyType, config.Algorithm)
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &SignAWSKMS{config: config, now: time.Now}, nil
+}
+
+func (sign *SignAWSKMS) isMac() bool {
+	return sign.config.Algorithm == AWSMacHMACSHA256
+}
+
+// Функція для заміни годинника, яким датуються запити SigV4
+func (sign *SignAWSKMS) SetClock(now func() time.Time) {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.now = now
+}
+
+// Функція для створення "сирого" підпису (Sign) або MAC (GenerateMac) у KMS
+func (sign *SignAWSKMS) Sign(message []byte) ([]byte, error) {
+	return sign.sign(context.Background(), message)
+}
+
+func (sign *SignAWSKMS) sign(ctx context.Context, message []byte) ([]byte, error) {
+	if sign.isMac() {
+		var response struct {
+			Mac []byte `json:"Mac"`
+		}
+		err := sign.call(ctx, "GenerateMac", map[string]any{
+			"KeyId":        sign.config.KeyID,
+			"Message":      message,
+			"MacAlgorithm": sign.config.Algorithm,
+		}, &response)
+		return response.Mac, err
+	}
+	var response struct {
+		Signature []byte `json:"Signature"`
+	}
+	err := sign.call(ctx, "Sign", map[string]any{
+		"KeyId":            sign.config.KeyID,
+		"Message":          message,
+		"MessageType":      "RAW",
+		"SigningAlgorithm": sign.config.Algorithm,
+	}, &response)
+	return response.Signature, err
+}
+
+// MAC повертається у hex, як signature.SignHMAC, підпис RSA - у Base64, як signature.SignRSA
+func (sign *SignAWSKMS) CreateSignature(queryString string) string {
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
+}
+
+// Функція для створення підпису з помилкою KMS, яку SignParams повертає викликачу
+func (sign *SignAWSKMS) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	signature, err := sign.sign(ctx, []byte(queryString))
+	switch {
+	case err != nil:
+		return "", err
+	case len(signature) == 0:
+		return "", fmt.Errorf("%w: empty signature", ErrResponse)
+	case sign.isMac():
+		return hex.EncodeToString(signature), nil
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Функція для валідації підпису операціями Verify/VerifyMac у KMS
+func (sign *SignAWSKMS) ValidateSignature(message, signature string) bool {
+	if sign.isMac() {
+		mac, err := hex.DecodeString(signature)
+		if err != nil {
+			return false
+		}
+		var response struct {
+			MacValid bool `json:"MacValid"`
+		}
+		err = sign.call(context.Background(), "VerifyMac", map[string]any{
+			"KeyId":        sign.config.KeyID,
+			"Message":      []byte(message),
+			"Mac":          mac,
+			"MacAlgorithm": sign.config.Algorithm,
+		}, &response)
+		return err == nil && response.MacValid
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	var response struct {
+		SignatureValid bool `json:"SignatureValid"`
+	}
+	err = sign.call(context.Background(), "Verify", map[string]any{
+		"KeyId":            sign.config.KeyID,
+		"Message":          []byte(message),
+		"MessageType":      "RAW",
+		"Signature":        signatureBytes,
+		"SigningAlgorithm": sign.config.Algorithm,
+	}, &response)
+	return err == nil && response.SignatureValid
+}
+
+func (sign *SignAWSKMS) GetAPIKey() string {
+	return sign.config.APIKey
+}
+
+// Реалізація signature.Destroyer: ключ лишається у KMS, підписувач відкидає облікові дані AWS
+// і далі повертає signature.ErrSignerDestroyed
+func (sign *SignAWSKMS) Destroy() {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.config.AccessKeyID = ""
+	sign.config.SecretAccessKey = ""
+	sign.config.SessionToken = ""
+	sign.destroyed = true
+}
+
+func (sign *SignAWSKMS) call(ctx context.Context, operation string, request, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(sign.config.Endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+operation)
+	sign.mutex.RLock()
+	destroyed := sign.destroyed
+	if !destroyed {
+		sign.signRequest(req, body)
+	}
+	sign.mutex.RUnlock()
+	if destroyed {
+		return signature.ErrSignerDestroyed
+	}
+	return doJSON(sign.config.HTTPClient, req, response)
+}
+
+// Функція для підпису запиту AWS Signature Version 4 для сервісу kms
+func (sign *SignAWSKMS) signRequest(req *http.Request, body []byte) {
+	now := sign.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sign.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sign.config.SessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if sign.config.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, header := range headers {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	payloadHash := sha256.Sum256(body)
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		url.Values(req.URL.Query()).Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + sign.config.Region + "/kms/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+sign.config.SecretAccessKey), date)
+	key = hmacSHA256(key, sign.config.Region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+sign.config.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
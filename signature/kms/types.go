@@ -0,0 +1,40 @@
+package kms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Підписувачі, ключі яких зберігаються у хмарних сервісах керування ключами:
+// HashiCorp Vault Transit та AWS KMS (або сумісні з ним API).
+// Результати перетворюються у кодування signature.SignHMAC (hex)
+// та signature.SignRSA / signature.SignEd25519 (Base64).
+
+var (
+	ErrKeyType  = errors.New("unsupported kms key type")
+	ErrResponse = errors.New("unexpected kms response")
+)
+
+// Функція для виконання JSON запиту до сервісу
+func doJSON(client *http.Client, req *http.Request, response any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d: %s", ErrResponse, resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("%w: %v", ErrResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+type (
+	VaultConfig struct {
+		Address    string // адреса Vault, напр. https://vault:8200
+		Token      string
+		Namespace  string // необов'язковий, для Vault Enterprise
+		Mount      string // шлях монтування Transit, за замовчуванням "transit"
+		Key        string // ім'я ключа у Transit
+		HMAC       bool   // true - ендпоінт hmac (HMAC-SHA256), false - ендпоінт sign
+		APIKey     string
+		HTTPClient *http.Client
+	}
+	// Підписувач Vault Transit. Підпис виконується у Vault,
+	// перевірка асиметричних підписів - публічним ключем, отриманим з Vault.
+	SignVault struct {
+		config    VaultConfig
+		publicKey crypto.PublicKey
+		mutex     sync.RWMutex // токен та ознака знищення
+		destroyed bool
+	}
+	vaultResponse struct {
+		Data json.RawMessage `json:"data"`
+	}
+	vaultKey struct {
+		Type          string                     `json:"type"`
+		LatestVersion int                        `json:"latest_version"`
+		Keys          map[string]json.RawMessage `json:"keys"`
+	}
+	vaultKeyVersion struct {
+		PublicKey string `json:"public_key"`
+	}
+)
+
+func NewSignVault(config VaultConfig) (*SignVault, error) {
+	if config.Mount == "" {
+		config.Mount = "transit"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	sign := &SignVault{config: config}
+	if config.HMAC {
+		return sign, nil
+	}
+
+	// Для ендпоінта sign потрібен публічний ключ останньої версії
+	var key vaultKey
+	if err := sign.call(context.Background(), http.MethodGet, "keys", nil, &key); err != nil {
+		return nil, err
+	}
+	var version vaultKeyVersion
+	if err := json.Unmarshal(key.Keys[strconv.Itoa(key.LatestVersion)], &version); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrResponse, err)
+	}
+	switch {
+	case key.Type == "ed25519":
+		publicKey, err := base64.StdEncoding.DecodeString(version.PublicKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: bad ed25519 public key", ErrResponse)
+		}
+		sign.publicKey = ed25519.PublicKey(publicKey)
+	case strings.HasPrefix(key.Type, "rsa-"):
+		block, _ := pem.Decode([]byte(version.PublicKey))
+		if block == nil {
+			return nil, fmt.Errorf("%w: bad rsa public key", ErrResponse)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: bad rsa public key", ErrResponse)
+		}
+		sign.publicKey = rsaKey
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrKeyType, key.Type)
+	}
+	return sign, nil
+}
+
+// Функція для створення "сирого" підпису або HMAC у Vault
+func (sign *SignVault) Sign(message []byte) ([]byte, error) {
+	return sign.sign(context.Background(), message)
+}
+
+func (sign *SignVault) sign(ctx context.Context, message []byte) ([]byte, error) {
+	input := base64.StdEncoding.EncodeToString(message)
+	var (
+		result string
+		err    error
+	)
+	if sign.config.HMAC {
+		var response struct {
+			HMAC string `json:"hmac"`
+		}
+		err = sign.call(ctx, http.MethodPost, "hmac/sha2-256", map[string]string{"input": input}, &response)
+		result = response.HMAC
+	} else {
+		request := map[string]string{"input": input}
+		action := "sign"
+		if _, ok := sign.publicKey.(*rsa.PublicKey); ok {
+			request["signature_algorithm"] = "pkcs1v15"
+			action = "sign/sha2-256"
+		}
+		var response struct {
+			Signature string `json:"signature"`
+		}
+		err = sign.call(ctx, http.MethodPost, action, request, &response)
+		result = response.Signature
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeVaultValue(result)
+}
+
+// HMAC повертається у hex, як signature.SignHMAC, підписи - у Base64
+func (sign *SignVault) CreateSignature(queryString string) string {
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
+}
+
+// Функція для створення підпису з помилкою Vault, яку SignParams повертає викликачу
+func (sign *SignVault) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	signature, err := sign.sign(ctx, []byte(queryString))
+	switch {
+	case err != nil:
+		return "", err
+	case len(signature) == 0:
+		return "", fmt.Errorf("%w: empty signature", ErrResponse)
+	case sign.config.HMAC:
+		return hex.EncodeToString(signature), nil
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (sign *SignVault) ValidateSignature(message, signature string) bool {
+	if sign.config.HMAC {
+		expected, err := sign.Sign([]byte(message))
+		if err != nil {
+			return false
+		}
+		actual, err := hex.DecodeString(signature)
+		return err == nil && hmac.Equal(expected, actual)
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch publicKey := sign.publicKey.(type) {
+	case *rsa.PublicKey:
+		hashed := sha256.Sum256([]byte(message))
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signatureBytes) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(publicKey, []byte(message), signatureBytes)
+	}
+	return false
+}
+
+func (sign *SignVault) GetAPIKey() string {
+	return sign.config.APIKey
+}
+
+// Реалізація signature.Destroyer: ключ лишається у Vault, підписувач відкидає токен
+// і далі повертає signature.ErrSignerDestroyed
+func (sign *SignVault) Destroy() {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.config.Token = ""
+	sign.destroyed = true
+}
+
+func (sign *SignVault) call(ctx context.Context, method, action string, request, response any) error {
+	sign.mutex.RLock()
+	token, destroyed := sign.config.Token, sign.destroyed
+	sign.mutex.RUnlock()
+	if destroyed {
+		return signature.ErrSignerDestroyed
+	}
+	var body bytes.Buffer
+	if request != nil {
+		if err := json.NewEncoder(&body).Encode(request); err != nil {
+			return err
+		}
+	}
+	parts := strings.SplitN(action, "/", 2)
+	path := "/v1/" + strings.Trim(sign.config.Mount, "/") + "/" + parts[0] + "/" + url.PathEscape(sign.config.Key)
+	if len(parts) == 2 {
+		path += "/" + parts[1]
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(sign.config.Address, "/")+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if sign.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", sign.config.Namespace)
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	var envelope vaultResponse
+	if err := doJSON(sign.config.HTTPClient, req, &envelope); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(envelope.Data, response); err != nil {
+		return fmt.Errorf("%w: %v", ErrResponse, err)
+	}
+	return nil
+}
+
+// Значення Vault мають формат vault:v<версія>:<base64>
+func decodeVaultValue(value string) ([]byte, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("%w: %q", ErrResponse, value)
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
@@ -0,0 +1,276 @@
+package signature
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Мінімальні інтерфейси метрик та трасування без залежностей від клієнтів
+// Prometheus чи OpenTelemetry. PrometheusRegistry віддає метрики у текстовому
+// форматі експозиції, Span повторює методи trace.Span з OpenTelemetry, тож
+// адаптер до справжнього SDK - кілька рядків. MemoryTracer збирає завершені
+// спани для тестів без колекторів.
+
+type (
+	Label struct {
+		Name  string
+		Value string
+	}
+	Metrics interface {
+		IncCounter(name string, labels ...Label)
+		ObserveHistogram(name string, value float64, labels ...Label)
+	}
+	Tracer interface {
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+	Span interface {
+		SetAttributes(attributes ...Label)
+		RecordError(err error)
+		SetStatus(code SpanStatusCode, description string)
+		End()
+	}
+	// Коди статусу як у go.opentelemetry.io/otel/codes
+	SpanStatusCode int
+)
+
+const (
+	SpanStatusUnset SpanStatusCode = iota
+	SpanStatusError
+	SpanStatusOK
+)
+
+// Межі кошиків гістограм за замовчуванням, у секундах: від 10 мкс до 1 с
+var DefaultHistogramBuckets = []float64{0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.1, 1}
+
+type (
+	PrometheusRegistry struct {
+		mutex      sync.Mutex
+		buckets    []float64
+		counters   map[string]map[string]float64 // ім'я -> мітки -> значення
+		histograms map[string]map[string]*histogram
+	}
+	histogram struct {
+		counts []uint64 // кумулятивні не зберігаються, лише за кошиками
+		count  uint64
+		sum    float64
+	}
+)
+
+// buckets - межі кошиків гістограм, nil - DefaultHistogramBuckets
+func NewPrometheusRegistry(buckets []float64) *PrometheusRegistry {
+	if buckets == nil {
+		buckets = DefaultHistogramBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusRegistry{
+		buckets:    buckets,
+		counters:   make(map[string]map[string]float64),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+func (registry *PrometheusRegistry) IncCounter(name string, labels ...Label) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	series, ok := registry.counters[name]
+	if !ok {
+		series = make(map[string]float64)
+		registry.counters[name] = series
+	}
+	series[formatLabels(labels)]++
+}
+
+func (registry *PrometheusRegistry) ObserveHistogram(name string, value float64, labels ...Label) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	series, ok := registry.histograms[name]
+	if !ok {
+		series = make(map[string]*histogram)
+		registry.histograms[name] = series
+	}
+	key := formatLabels(labels)
+	h, ok := series[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(registry.buckets))}
+		series[key] = h
+	}
+	if i := sort.SearchFloat64s(registry.buckets, value); i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += value
+}
+
+// Значення лічильника для мітки, для тестів та діагностики
+func (registry *PrometheusRegistry) Counter(name string, labels ...Label) float64 {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	return registry.counters[name][formatLabels(labels)]
+}
+
+// Функція для запису метрик у текстовому форматі експозиції Prometheus
+func (registry *PrometheusRegistry) WriteTo(w io.Writer) (int64, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	var out strings.Builder
+	for _, name := range sortedKeys(registry.counters) {
+		fmt.Fprintf(&out, "# TYPE %s counter\n", name)
+		series := registry.counters[name]
+		for _, labels := range sortedKeys(series) {
+			fmt.Fprintf(&out, "%s%s %s\n", name, labels, formatFloat(series[labels]))
+		}
+	}
+	for _, name := range sortedKeys(registry.histograms) {
+		fmt.Fprintf(&out, "# TYPE %s histogram\n", name)
+		series := registry.histograms[name]
+		for _, labels := range sortedKeys(series) {
+			h := series[labels]
+			var cumulative uint64
+			for i, bound := range registry.buckets {
+				cumulative += h.counts[i]
+				fmt.Fprintf(&out, "%s_bucket%s %d\n", name, withLabel(labels, "le", formatFloat(bound)), cumulative)
+			}
+			fmt.Fprintf(&out, "%s_bucket%s %d\n", name, withLabel(labels, "le", "+Inf"), h.count)
+			fmt.Fprintf(&out, "%s_sum%s %s\n", name, labels, formatFloat(h.sum))
+			fmt.Fprintf(&out, "%s_count%s %d\n", name, labels, h.count)
+		}
+	}
+	written, err := io.WriteString(w, out.String())
+	return int64(written), err
+}
+
+// Обробник HTTP для ендпоінта /metrics
+func (registry *PrometheusRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	registry.WriteTo(w)
+}
+
+// Мітки у форматі {a="1",b="2"}, порядок - як передано
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		out.WriteString(label.Name)
+		out.WriteString(`="`)
+		out.WriteString(escapeLabelValue(label.Value))
+		out.WriteByte('"')
+	}
+	out.WriteByte('}')
+	return out.String()
+}
+
+func withLabel(labels, name, value string) string {
+	label := name + `="` + value + `"`
+	if labels == "" {
+		return "{" + label + "}"
+	}
+	return labels[:len(labels)-1] + "," + label + "}"
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type (
+	MemoryTracer struct {
+		mutex sync.Mutex
+		spans []SpanRecord
+		now   func() time.Time
+	}
+	// Завершений спан MemoryTracer
+	SpanRecord struct {
+		Name        string
+		Attributes  []Label
+		Errors      []error
+		Status      SpanStatusCode
+		Description string
+		Start       time.Time
+		End         time.Time
+	}
+	memorySpan struct {
+		tracer *MemoryTracer
+		record SpanRecord
+	}
+)
+
+func NewMemoryTracer() *MemoryTracer {
+	return &MemoryTracer{now: time.Now}
+}
+
+func (tracer *MemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, &memorySpan{tracer: tracer, record: SpanRecord{Name: name, Start: tracer.time()}}
+}
+
+// Функція для заміни годинника, яким позначаються початок та кінець спанів
+func (tracer *MemoryTracer) SetClock(now func() time.Time) {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+	tracer.now = now
+}
+
+// Функція для отримання копії завершених спанів
+func (tracer *MemoryTracer) Spans() []SpanRecord {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+	return append([]SpanRecord(nil), tracer.spans...)
+}
+
+func (tracer *MemoryTracer) time() time.Time {
+	tracer.mutex.Lock()
+	now := tracer.now
+	tracer.mutex.Unlock()
+	return now()
+}
+
+func (span *memorySpan) SetAttributes(attributes ...Label) {
+	span.record.Attributes = append(span.record.Attributes, attributes...)
+}
+
+func (span *memorySpan) RecordError(err error) {
+	span.record.Errors = append(span.record.Errors, err)
+}
+
+func (span *memorySpan) SetStatus(code SpanStatusCode, description string) {
+	span.record.Status = code
+	span.record.Description = description
+}
+
+func (span *memorySpan) End() {
+	span.record.End = span.tracer.time()
+	span.tracer.mutex.Lock()
+	defer span.tracer.mutex.Unlock()
+	span.tracer.spans = append(span.tracer.spans, span.record)
+}
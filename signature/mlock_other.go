@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package signature
+
+func mlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return ErrMlockUnsupported
+}
+
+func munlock(b []byte) {}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package signature
+
+import "syscall"
+
+func mlock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return syscall.Mlock(b)
+}
+
+func munlock(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	_ = syscall.Munlock(b)
+}
@@ -0,0 +1,238 @@
+package signature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// Впорядкований набір параметрів запиту - основний шлях підпису параметрів:
+// SignParams та ValidateParams підписують їх будь-яким Sign лише через
+// CreateSignature та ValidateSignature. simplejson, url.Values та map
+// перетворюються на Params адаптерами (ParamsFromSimpleJSON, ParamsFromValues, ParamsFromMap).
+// Значення зберігаються вже відформатованими рядками, записи - відсортованими
+// за ключем (повтори ключа - у порядку додавання), тому канонічний рядок
+// збігається з url.Values.Encode() та ConvertSimpleJSONToString і будується без сортування.
+
+type (
+	Params struct {
+		entries []Param
+	}
+	Param struct {
+		Key   string
+		Value string
+	}
+)
+
+func NewParams() *Params {
+	return &Params{}
+}
+
+// Функція для створення параметрів з url.Values, повтори ключа зберігаються
+func ParamsFromValues(values url.Values) *Params {
+	params := &Params{entries: make([]Param, 0, len(values))}
+	for key, list := range values {
+		for _, value := range list {
+			params.entries = append(params.entries, Param{Key: key, Value: value})
+		}
+	}
+	sort.SliceStable(params.entries, func(i, j int) bool { return params.entries[i].Key < params.entries[j].Key })
+	return params
+}
+
+// Функція для створення параметрів з map, значення форматуються як у ConvertSimpleJSONToString
+func ParamsFromMap(values map[string]any) *Params {
+	params := &Params{entries: make([]Param, 0, len(values))}
+	for key, value := range values {
+		params.entries = append(params.entries, Param{Key: key, Value: FormatParamValue(value)})
+	}
+	sort.Slice(params.entries, func(i, j int) bool { return params.entries[i].Key < params.entries[j].Key })
+	return params
+}
+
+// Функція для форматування значення за правилами канонічного рядка (як fmt %v)
+func FormatParamValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return string(value)
+	}
+	var scratch [64]byte
+	return string(appendRawValue(scratch[:0], value))
+}
+
+// Індекс першого запису з ключем key або місце для вставки
+func (params *Params) search(key string) int {
+	return sort.Search(len(params.entries), func(i int) bool { return params.entries[i].Key >= key })
+}
+
+// Функція для встановлення єдиного значення ключа
+func (params *Params) Set(key, value string) *Params {
+	i := params.search(key)
+	end := i
+	for end < len(params.entries) && params.entries[end].Key == key {
+		end++
+	}
+	switch {
+	case end == i:
+		params.entries = append(params.entries, Param{})
+		copy(params.entries[i+1:], params.entries[i:])
+		params.entries[i] = Param{Key: key, Value: value}
+	default:
+		params.entries[i].Value = value
+		params.entries = append(params.entries[:i+1], params.entries[end:]...)
+	}
+	return params
+}
+
+// Функція для додавання ще одного значення ключа (key=a&key=b)
+func (params *Params) Add(key, value string) *Params {
+	i := params.search(key)
+	for i < len(params.entries) && params.entries[i].Key == key {
+		i++
+	}
+	params.entries = append(params.entries, Param{})
+	copy(params.entries[i+1:], params.entries[i:])
+	params.entries[i] = Param{Key: key, Value: value}
+	return params
+}
+
+func (params *Params) SetString(key, value string) *Params {
+	return params.Set(key, value)
+}
+
+func (params *Params) SetInt64(key string, value int64) *Params {
+	return params.Set(key, strconv.FormatInt(value, 10))
+}
+
+// Десяткове значення (напр. shopspring/decimal.Decimal) передається як fmt.Stringer,
+// щоб ціна та кількість не проходили через float64
+func (params *Params) SetDecimal(key string, value fmt.Stringer) *Params {
+	return params.Set(key, value.String())
+}
+
+func (params *Params) SetBool(key string, value bool) *Params {
+	return params.Set(key, strconv.FormatBool(value))
+}
+
+// Список кодується масивом JSON (["BTCUSDT","ETHUSDT"]), як очікують біржові API
+func (params *Params) SetList(key string, values ...string) *Params {
+	if values == nil {
+		values = []string{}
+	}
+	list, _ := json.Marshal(values)
+	return params.Set(key, string(list))
+}
+
+// Функція для отримання першого значення ключа
+func (params *Params) Get(key string) (string, bool) {
+	i := params.search(key)
+	if i < len(params.entries) && params.entries[i].Key == key {
+		return params.entries[i].Value, true
+	}
+	return "", false
+}
+
+// Функція для отримання всіх значень ключа
+func (params *Params) GetAll(key string) (values []string) {
+	for i := params.search(key); i < len(params.entries) && params.entries[i].Key == key; i++ {
+		values = append(values, params.entries[i].Value)
+	}
+	return
+}
+
+func (params *Params) Del(key string) *Params {
+	i := params.search(key)
+	end := i
+	for end < len(params.entries) && params.entries[end].Key == key {
+		end++
+	}
+	params.entries = append(params.entries[:i], params.entries[end:]...)
+	return params
+}
+
+func (params *Params) Len() int {
+	return len(params.entries)
+}
+
+// Записи у канонічному порядку, зріз не можна змінювати
+func (params *Params) Entries() []Param {
+	return params.entries
+}
+
+func (params *Params) Clone() *Params {
+	return &Params{entries: append([]Param(nil), params.entries...)}
+}
+
+func (params *Params) Values() url.Values {
+	values := make(url.Values, len(params.entries))
+	for _, entry := range params.entries {
+		values[entry.Key] = append(values[entry.Key], entry.Value)
+	}
+	return values
+}
+
+// Функція для дописування канонічного рядка у dst
+func (params *Params) AppendCanonical(dst []byte) []byte {
+	return params.appendCanonical(dst, "")
+}
+
+func (params *Params) appendCanonical(dst []byte, skip string) []byte {
+	first := true
+	for _, entry := range params.entries {
+		if skip != "" && entry.Key == skip {
+			continue
+		}
+		if !first {
+			dst = append(dst, '&')
+		}
+		first = false
+		dst = appendQueryEscape(dst, entry.Key)
+		dst = append(dst, '=')
+		dst = appendQueryEscape(dst, entry.Value)
+	}
+	return dst
+}
+
+// Канонічний рядок, він же закодований query string чи тіло form-urlencoded
+func (params *Params) Encode() string {
+	return params.canonicalString("")
+}
+
+func (params *Params) canonicalString(skip string) string {
+	buffer := canonicalBuffers.Get().(*[]byte)
+	*buffer = params.appendCanonical((*buffer)[:0], skip)
+	result := string(*buffer)
+	canonicalBuffers.Put(buffer)
+	return result
+}
+
+// Функція для підпису параметрів, повертає копію з полем signature.
+// Невдача підписувача повертається помилкою, як у SignParameters.
+func SignParams(params *Params, sign Sign) (*Params, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+	signed := params.Clone().Del("signature")
+	signature, err := createSignature(context.Background(), sign, signed.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return signed.Set("signature", signature), nil
+}
+
+// Функція для валідації підписаних параметрів
+func ValidateParams(params *Params, sign Sign) bool {
+	if params == nil {
+		return false
+	}
+	signature, ok := params.Get("signature")
+	if !ok {
+		return false
+	}
+	return sign.ValidateSignature(params.canonicalString("signature"), signature)
+}
@@ -0,0 +1,86 @@
+package signature_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type decimal string
+
+func (d decimal) String() string { return string(d) }
+
+// Test 1: Typed setters keep keys sorted
+func TestParamsSetters(t *testing.T) {
+	params := signature.NewParams().
+		SetString("symbol", "BTCUSDT").
+		SetInt64("timestamp", 1610612740000).
+		SetDecimal("price", decimal("52000.10")).
+		SetBool("reduceOnly", true).
+		SetList("symbols", "BTCUSDT", "ETHUSDT").
+		SetString("side", "BUY")
+	assert.Equal(t, 6, params.Len())
+	assert.Equal(t, "price=52000.10&reduceOnly=true&side=BUY&symbol=BTCUSDT&"+
+		"symbols=%5B%22BTCUSDT%22%2C%22ETHUSDT%22%5D&timestamp=1610612740000", params.Encode())
+
+	params.Set("side", "SELL").Add("side", "BUY").Del("reduceOnly")
+	value, ok := params.Get("side")
+	assert.True(t, ok)
+	assert.Equal(t, "SELL", value)
+	assert.Equal(t, []string{"SELL", "BUY"}, params.Values()["side"])
+	params.Set("side", "BUY")
+	assert.Equal(t, []string{"BUY"}, params.Values()["side"])
+	_, ok = params.Get("reduceOnly")
+	assert.False(t, ok)
+	assert.Equal(t, `symbols=%5B%5D`, signature.NewParams().SetList("symbols").Encode())
+}
+
+// Test 2: Adapters produce the same canonical string as ConvertSimpleJSONToString
+func TestParamsAdapters(t *testing.T) {
+	values := orderParams()
+	js := simplejson.New()
+	for key, value := range values {
+		js.Set(key, value)
+	}
+	expected, err := signature.ConvertSimpleJSONToString(js)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, signature.ParamsFromMap(values).Encode())
+	assert.Equal(t, expected, signature.ParamsFromSimpleJSON(js).Encode())
+
+	query := url.Values{"b": {"2", "1"}, "a": {"x y"}}
+	params := signature.ParamsFromValues(query)
+	assert.Equal(t, query.Encode(), params.Encode())
+	assert.Equal(t, query, params.Values())
+}
+
+// Test 3: Sign and validate Params
+func TestSignParams(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	params := signature.NewParams().SetInt64("timestamp", 1610612740000)
+	signed, err := signature.SignParams(params, sign)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, params.Len())
+	assert.Equal(t, "signature=b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66&timestamp=1610612740000", signed.Encode())
+	assert.True(t, signature.ValidateParams(signed, sign))
+	assert.False(t, signature.ValidateParams(signed.Clone().SetInt64("timestamp", 1), sign))
+	assert.False(t, signature.ValidateParams(params, sign))
+	assert.False(t, signature.ValidateParams(nil, sign))
+	_, err = signature.SignParams(nil, sign)
+	assert.ErrorIs(t, err, signature.ErrNilParams)
+
+	// Підпис сумісний з SignParameters
+	js, err := sign.SignParameters(simplejson.New())
+	assert.Nil(t, err)
+	empty, err := signature.SignParams(signature.NewParams(), sign)
+	assert.Nil(t, err)
+	value, _ := empty.Get("signature")
+	assert.Equal(t, js.Get("signature").MustString(), value)
+
+	// Невдача підписувача - помилка, а не порожній підпис
+	sign.Destroy()
+	_, err = signature.SignParams(params, sign)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+}
@@ -0,0 +1,342 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Політика підпису: обгортка над будь-яким Sign, що перевіряє метод, ендпоінт
+// та параметри запиту перед підписом. Правила:
+//
+//	endpoints          - дозволені ендпоінти (метод та шлях, "*" у кінці шляху - префікс)
+//	                     з необов'язковою квотою підписів за хвилину. Шлях запиту
+//	                     нормалізується path.Clean, шляхи з ".." відхиляються
+//	symbols            - дозволені символи (параметр symbol)
+//	max_notional       - найбільший обсяг ордера: price*quantity або quoteOrderQty
+//	withdraw_addresses - дозволені адреси виведення (параметр address)
+//	quota_per_minute   - загальна квота підписів за хвилину
+//
+// Порожнє правило не обмежує. Відмови повертаються як *PolicyError,
+// що розгортається у ErrPolicyDenied та сентинел конкретного правила.
+
+var (
+	ErrPolicyDenied   = errors.New("signing denied by policy")
+	ErrPolicyEndpoint = errors.New("endpoint is not allowed")
+	ErrPolicySymbol   = errors.New("symbol is not allowed")
+	ErrPolicyNotional = errors.New("order notional exceeds limit")
+	ErrPolicyAddress  = errors.New("withdrawal address is not allowed")
+	ErrPolicyQuota    = errors.New("signing quota exceeded")
+)
+
+const (
+	policyQuotaWindow = time.Minute
+	policyGlobalQuota = ""
+)
+
+type (
+	Policy struct {
+		Endpoints         []EndpointRule `json:"endpoints,omitempty"`
+		Symbols           []string       `json:"symbols,omitempty"`
+		MaxNotional       string         `json:"max_notional,omitempty"` // десяткове число, напр. "10000.5"
+		WithdrawAddresses []string       `json:"withdraw_addresses,omitempty"`
+		QuotaPerMinute    int            `json:"quota_per_minute,omitempty"`
+	}
+	EndpointRule struct {
+		Method         string `json:"method,omitempty"` // порожній - будь-який метод
+		Path           string `json:"path"`
+		QuotaPerMinute int    `json:"quota_per_minute,omitempty"`
+	}
+	PolicyError struct {
+		Rule   error // один з ErrPolicyEndpoint, ErrPolicySymbol, ErrPolicyNotional, ErrPolicyAddress, ErrPolicyQuota
+		Detail string
+	}
+	SignPolicy struct {
+		sign        Sign
+		policy      Policy
+		maxNotional *big.Rat
+		mutex       sync.Mutex
+		// Моменти підписів за останню хвилину: загальні та за шляхом правила ендпоінта
+		quotas map[string][]time.Time
+		now    func() time.Time
+	}
+)
+
+func (err *PolicyError) Error() string {
+	return fmt.Sprintf("%v: %v: %s", ErrPolicyDenied, err.Rule, err.Detail)
+}
+
+func (err *PolicyError) Unwrap() []error {
+	return []error{ErrPolicyDenied, err.Rule}
+}
+
+// Функція для завантаження політики з JSON файлу
+func LoadPolicy(path string) (Policy, error) {
+	var policy Policy
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return policy, fmt.Errorf("error parsing policy %s: %v", path, err)
+	}
+	return policy, nil
+}
+
+func NewSignPolicy(sign Sign, policy Policy) (*SignPolicy, error) {
+	signPolicy := &SignPolicy{
+		sign:   sign,
+		policy: policy,
+		quotas: make(map[string][]time.Time),
+		now:    time.Now,
+	}
+	if policy.MaxNotional != "" {
+		maxNotional, ok := new(big.Rat).SetString(policy.MaxNotional)
+		if !ok {
+			return nil, fmt.Errorf("invalid max_notional %q", policy.MaxNotional)
+		}
+		signPolicy.maxNotional = maxNotional
+	}
+	return signPolicy, nil
+}
+
+// Функція для заміни годинника, за яким рахуються квоти
+func (signPolicy *SignPolicy) SetClock(now func() time.Time) {
+	signPolicy.mutex.Lock()
+	defer signPolicy.mutex.Unlock()
+	signPolicy.now = now
+}
+
+// Функція для перевірки та підпису запиту, повертає копію параметрів з полем signature
+func (signPolicy *SignPolicy) SignRequest(method, endpoint string, params *Params) (*Params, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+	release, err := signPolicy.authorize(method, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := SignParams(params, signPolicy.sign)
+	if err != nil {
+		release()
+	}
+	return signed, err
+}
+
+// Функція для перевірки запиту без підпису та без урахування у квотах
+func (signPolicy *SignPolicy) Check(method, endpoint string, params *Params) error {
+	if params == nil {
+		return ErrNilParams
+	}
+	signPolicy.mutex.Lock()
+	defer signPolicy.mutex.Unlock()
+	_, err := signPolicy.check(method, endpoint, params)
+	return err
+}
+
+// Підпис без відомого ендпоінта: дозволений, лише якщо політика не обмежує ендпоінти.
+// Рядок розбирається як query string, щоб перевірити параметри.
+func (signPolicy *SignPolicy) CreateSignature(queryString string) string {
+	values, err := url.ParseQuery(queryString)
+	if err != nil {
+		return ""
+	}
+	release, err := signPolicy.authorize("", "", ParamsFromValues(values))
+	if err != nil {
+		return ""
+	}
+	signature := signPolicy.sign.CreateSignature(queryString)
+	if signature == "" {
+		release()
+	}
+	return signature
+}
+
+// Функція для створення підпису з помилкою: відмова політики повертається як *PolicyError
+func (signPolicy *SignPolicy) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	values, err := url.ParseQuery(queryString)
+	if err != nil {
+		return "", err
+	}
+	release, err := signPolicy.authorize("", "", ParamsFromValues(values))
+	if err != nil {
+		return "", err
+	}
+	signature, err := createSignature(ctx, signPolicy.sign, queryString)
+	if err != nil {
+		release()
+	}
+	return signature, err
+}
+
+func (signPolicy *SignPolicy) ValidateSignature(message, signature string) bool {
+	return signPolicy.sign.ValidateSignature(message, signature)
+}
+
+func (signPolicy *SignPolicy) GetAPIKey() string {
+	return signPolicy.sign.GetAPIKey()
+}
+
+// Перевірка правил та облік квот під одним м'ютексом. Підпис резервується у квотах
+// до виклику підписувача, щоб паралельні запити не перевищили квоту, а release
+// повертає резерв, якщо підписати не вдалося.
+func (signPolicy *SignPolicy) authorize(method, endpoint string, params *Params) (release func(), err error) {
+	signPolicy.mutex.Lock()
+	defer signPolicy.mutex.Unlock()
+	rule, err := signPolicy.check(method, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	now := signPolicy.now()
+	var keys []string
+	if signPolicy.policy.QuotaPerMinute > 0 {
+		keys = append(keys, policyGlobalQuota)
+	}
+	if rule != nil && rule.QuotaPerMinute > 0 {
+		keys = append(keys, rule.Method+" "+rule.Path)
+	}
+	for _, key := range keys {
+		signPolicy.quotas[key] = append(signPolicy.quotas[key], now)
+	}
+	return func() { signPolicy.release(keys, now) }, nil
+}
+
+func (signPolicy *SignPolicy) release(keys []string, moment time.Time) {
+	signPolicy.mutex.Lock()
+	defer signPolicy.mutex.Unlock()
+	for _, key := range keys {
+		moments := signPolicy.quotas[key]
+		if i := slices.Index(moments, moment); i >= 0 {
+			signPolicy.quotas[key] = slices.Delete(moments, i, i+1)
+		}
+	}
+}
+
+func (signPolicy *SignPolicy) check(method, endpoint string, params *Params) (*EndpointRule, error) {
+	policy := &signPolicy.policy
+	var rule *EndpointRule
+	if len(policy.Endpoints) > 0 {
+		cleaned, ok := cleanEndpoint(endpoint)
+		for i := 0; ok && i < len(policy.Endpoints); i++ {
+			if policy.Endpoints[i].matches(method, cleaned) {
+				rule = &policy.Endpoints[i]
+				break
+			}
+		}
+		if rule == nil {
+			return nil, &PolicyError{Rule: ErrPolicyEndpoint, Detail: strings.TrimSpace(method + " " + endpoint)}
+		}
+	}
+
+	// Перевіряються всі значення повтореного ключа, біржа може взяти будь-яке з них
+	if len(policy.Symbols) > 0 {
+		for _, symbol := range params.GetAll("symbol") {
+			if !slices.Contains(policy.Symbols, symbol) {
+				return nil, &PolicyError{Rule: ErrPolicySymbol, Detail: symbol}
+			}
+		}
+	}
+
+	if len(policy.WithdrawAddresses) > 0 {
+		for _, address := range params.GetAll("address") {
+			if !slices.Contains(policy.WithdrawAddresses, address) {
+				return nil, &PolicyError{Rule: ErrPolicyAddress, Detail: address}
+			}
+		}
+	}
+
+	if signPolicy.maxNotional != nil {
+		if err := signPolicy.checkNotional(params); err != nil {
+			return nil, err
+		}
+	}
+
+	now := signPolicy.now()
+	if policy.QuotaPerMinute > 0 && signPolicy.used(policyGlobalQuota, now) >= policy.QuotaPerMinute {
+		return nil, &PolicyError{Rule: ErrPolicyQuota, Detail: fmt.Sprintf("%d per minute", policy.QuotaPerMinute)}
+	}
+	if rule != nil && rule.QuotaPerMinute > 0 && signPolicy.used(rule.Method+" "+rule.Path, now) >= rule.QuotaPerMinute {
+		return nil, &PolicyError{Rule: ErrPolicyQuota, Detail: fmt.Sprintf("%d per minute for %s", rule.QuotaPerMinute, rule.Path)}
+	}
+	return rule, nil
+}
+
+// Обсяг ордера: quoteOrderQty або price*quantity. Ордер з кількістю, але без ціни
+// (ринковий), відхиляється, бо його обсяг неможливо оцінити.
+func (signPolicy *SignPolicy) checkNotional(params *Params) error {
+	for _, key := range []string{"price", "quantity", "quoteOrderQty"} {
+		if len(params.GetAll(key)) > 1 {
+			return &PolicyError{Rule: ErrPolicyNotional, Detail: "duplicate " + key}
+		}
+	}
+	var notional *big.Rat
+	if quote, ok := params.Get("quoteOrderQty"); ok {
+		value, ok := new(big.Rat).SetString(quote)
+		if !ok {
+			return &PolicyError{Rule: ErrPolicyNotional, Detail: "invalid quoteOrderQty " + quote}
+		}
+		notional = value
+	} else if quantity, ok := params.Get("quantity"); ok {
+		price, ok := params.Get("price")
+		if !ok {
+			return &PolicyError{Rule: ErrPolicyNotional, Detail: "notional of order without price is unknown"}
+		}
+		priceValue, okPrice := new(big.Rat).SetString(price)
+		quantityValue, okQuantity := new(big.Rat).SetString(quantity)
+		if !okPrice || !okQuantity {
+			return &PolicyError{Rule: ErrPolicyNotional, Detail: "invalid price or quantity"}
+		}
+		notional = priceValue.Mul(priceValue, quantityValue)
+	}
+	if notional != nil && notional.Abs(notional).Cmp(signPolicy.maxNotional) > 0 {
+		return &PolicyError{Rule: ErrPolicyNotional, Detail: notional.FloatString(8) + " > " + signPolicy.policy.MaxNotional}
+	}
+	return nil
+}
+
+// Кількість підписів у вікні квоти, застарілі моменти відкидаються
+func (signPolicy *SignPolicy) used(key string, now time.Time) int {
+	moments := signPolicy.quotas[key]
+	start := 0
+	for start < len(moments) && !moments[start].After(now.Add(-policyQuotaWindow)) {
+		start++
+	}
+	moments = moments[start:]
+	signPolicy.quotas[key] = moments
+	return len(moments)
+}
+
+// Нормалізований шлях запиту: "//" та "/./" прибираються path.Clean, а ".." (зокрема
+// закодоване %2e) відхиляється, бо інакше "/api/v3/../sapi/v1/withdraw" проходив би
+// правило "/api/v3/*", тоді як сервер звертається до /sapi/v1/withdraw
+func cleanEndpoint(endpoint string) (string, bool) {
+	if endpoint == "" {
+		return "", true
+	}
+	if strings.Contains(strings.ReplaceAll(strings.ToLower(endpoint), "%2e", "."), "..") {
+		return "", false
+	}
+	return path.Clean(endpoint), true
+}
+
+func (rule EndpointRule) matches(method, endpoint string) bool {
+	if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(rule.Path, "*"); ok {
+		return strings.HasPrefix(endpoint, prefix)
+	}
+	return rule.Path == endpoint
+}
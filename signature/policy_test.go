@@ -0,0 +1,167 @@
+package signature_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `{
+	"endpoints": [
+		{"method": "POST", "path": "/api/v3/order", "quota_per_minute": 2},
+		{"method": "GET", "path": "/api/v3/*"},
+		{"method": "POST", "path": "/sapi/v1/capital/withdraw/apply"}
+	],
+	"symbols": ["BTCUSDT", "ETHUSDT"],
+	"max_notional": "1000",
+	"withdraw_addresses": ["bc1qtrusted"],
+	"quota_per_minute": 10
+}`
+
+func testSignPolicy(t *testing.T) (*signature.SignPolicy, *time.Time) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	assert.Nil(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	policy, err := signature.LoadPolicy(path)
+	assert.Nil(t, err)
+	signPolicy, err := signature.NewSignPolicy(signature.NewSignHMAC("apy_key", "apy_secret"), policy)
+	assert.Nil(t, err)
+	now := time.Unix(1610612740, 0)
+	signPolicy.SetClock(func() time.Time { return now })
+	return signPolicy, &now
+}
+
+func orderRequestParams(symbol, price, quantity string) *signature.Params {
+	return signature.NewParams().
+		SetString("symbol", symbol).
+		SetString("price", price).
+		SetString("quantity", quantity).
+		SetInt64("timestamp", 1610612740000)
+}
+
+// Test 1: Allowed request is signed
+func TestSignPolicyAllowed(t *testing.T) {
+	signPolicy, _ := testSignPolicy(t)
+	signed, err := signPolicy.SignRequest("POST", "/api/v3/order", orderRequestParams("BTCUSDT", "50000", "0.02"))
+	assert.Nil(t, err)
+	assert.True(t, signature.ValidateParams(signed, signPolicy))
+	_, err = signPolicy.SignRequest("get", "/api/v3/account", signature.NewParams().SetInt64("timestamp", 1))
+	assert.Nil(t, err)
+	// Шлях нормалізується до порівняння з правилами
+	_, err = signPolicy.SignRequest("GET", "/api/v3//./account", signature.NewParams().SetInt64("timestamp", 1))
+	assert.Nil(t, err)
+}
+
+// Test 2: Each rule returns a typed denial
+func TestSignPolicyDenied(t *testing.T) {
+	signPolicy, _ := testSignPolicy(t)
+	tests := []struct {
+		method, endpoint string
+		params           *signature.Params
+		rule             error
+	}{
+		{"DELETE", "/api/v3/order", orderRequestParams("BTCUSDT", "1", "1"), signature.ErrPolicyEndpoint},
+		{"GET", "/api/v3/../sapi/v1/capital/withdraw/apply", signature.NewParams().SetString("address", "bc1qattacker"), signature.ErrPolicyEndpoint},
+		{"GET", "/api/v3/%2E%2e/sapi/v1/capital/withdraw/apply", signature.NewParams().SetString("address", "bc1qattacker"), signature.ErrPolicyEndpoint},
+		{"GET", "/api/v3/account/..", signature.NewParams().SetInt64("timestamp", 1), signature.ErrPolicyEndpoint},
+		{"POST", "/api/v3/order", orderRequestParams("DOGEUSDT", "1", "1"), signature.ErrPolicySymbol},
+		{"POST", "/api/v3/order", orderRequestParams("BTCUSDT", "1", "1").Add("symbol", "DOGEUSDT"), signature.ErrPolicySymbol},
+		{"POST", "/api/v3/order", orderRequestParams("BTCUSDT", "50000", "0.03"), signature.ErrPolicyNotional},
+		{"POST", "/api/v3/order", signature.NewParams().SetString("symbol", "BTCUSDT").SetString("quantity", "1"), signature.ErrPolicyNotional},
+		{"POST", "/api/v3/order", signature.NewParams().SetString("quoteOrderQty", "1000.01"), signature.ErrPolicyNotional},
+		{"POST", "/sapi/v1/capital/withdraw/apply", signature.NewParams().SetString("address", "bc1qattacker"), signature.ErrPolicyAddress},
+	}
+	for _, test := range tests {
+		_, err := signPolicy.SignRequest(test.method, test.endpoint, test.params)
+		assert.ErrorIs(t, err, signature.ErrPolicyDenied, test.params.Encode())
+		assert.ErrorIs(t, err, test.rule, test.params.Encode())
+		var policyError *signature.PolicyError
+		assert.ErrorAs(t, err, &policyError)
+	}
+	assert.Nil(t, signPolicy.Check("POST", "/sapi/v1/capital/withdraw/apply", signature.NewParams().SetString("address", "bc1qtrusted")))
+}
+
+// Test 3: Per-minute quotas
+func TestSignPolicyQuota(t *testing.T) {
+	signPolicy, now := testSignPolicy(t)
+	for i := 0; i < 2; i++ {
+		_, err := signPolicy.SignRequest("POST", "/api/v3/order", orderRequestParams("BTCUSDT", "1", "1"))
+		assert.Nil(t, err)
+	}
+	_, err := signPolicy.SignRequest("POST", "/api/v3/order", orderRequestParams("BTCUSDT", "1", "1"))
+	assert.ErrorIs(t, err, signature.ErrPolicyQuota)
+	// Інші ендпоінти обмежені лише загальною квотою
+	for i := 0; i < 8; i++ {
+		_, err = signPolicy.SignRequest("GET", "/api/v3/account", signature.NewParams())
+		assert.Nil(t, err)
+	}
+	_, err = signPolicy.SignRequest("GET", "/api/v3/account", signature.NewParams())
+	assert.ErrorIs(t, err, signature.ErrPolicyQuota)
+
+	*now = now.Add(time.Minute)
+	_, err = signPolicy.SignRequest("POST", "/api/v3/order", orderRequestParams("BTCUSDT", "1", "1"))
+	assert.Nil(t, err)
+}
+
+// Test 4: Sign interface without endpoint is denied when endpoints are restricted
+func TestSignPolicySignInterface(t *testing.T) {
+	signPolicy, _ := testSignPolicy(t)
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	_, err := signPolicy.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrPolicyEndpoint)
+	assert.Empty(t, signPolicy.CreateSignature("timestamp=1610612740000"))
+
+	open, err := signature.NewSignPolicy(signature.NewSignHMAC("apy_key", "apy_secret"), signature.Policy{Symbols: []string{"BTCUSDT"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", open.CreateSignature("timestamp=1610612740000"))
+	assert.Empty(t, open.CreateSignature("symbol=DOGEUSDT&timestamp=1610612740000"))
+	signed, err := open.SignParameters(params)
+	assert.Nil(t, err)
+	assert.True(t, open.ValidateSignatureParams(signed))
+	assert.Equal(t, "apy_key", open.GetAPIKey())
+
+	_, err = signature.NewSignPolicy(open, signature.Policy{MaxNotional: "many"})
+	assert.NotNil(t, err)
+}
+
+// Test 5: Failed signatures do not use the quota
+func TestSignPolicyQuotaSignerFailure(t *testing.T) {
+	fake := signaturetest.NewFakeSigner("apy_key")
+	signPolicy, err := signature.NewSignPolicy(fake, signature.Policy{
+		Endpoints:      []signature.EndpointRule{{Method: "POST", Path: "/api/v3/order", QuotaPerMinute: 1}},
+		QuotaPerMinute: 1,
+	})
+	assert.Nil(t, err)
+	sealed := errors.New("vault is sealed")
+	fake.SetError(sealed)
+	for i := 0; i < 3; i++ {
+		_, err = signPolicy.SignRequest("POST", "/api/v3/order", signature.NewParams().SetInt64("timestamp", 1))
+		assert.ErrorIs(t, err, sealed)
+		_, err = signPolicy.CreateSignatureContext(context.Background(), "timestamp=1")
+		assert.ErrorIs(t, err, signature.ErrPolicyEndpoint)
+	}
+	fake.SetError(nil)
+	_, err = signPolicy.SignRequest("POST", "/api/v3/order", signature.NewParams().SetInt64("timestamp", 1))
+	assert.Nil(t, err)
+	_, err = signPolicy.SignRequest("POST", "/api/v3/order", signature.NewParams().SetInt64("timestamp", 2))
+	assert.ErrorIs(t, err, signature.ErrPolicyQuota)
+
+	// Sign без ендпоінта: порожній підпис теж не витрачає квоту
+	open, err := signature.NewSignPolicy(fake, signature.Policy{QuotaPerMinute: 1})
+	assert.Nil(t, err)
+	fake.SetError(sealed)
+	assert.Empty(t, open.CreateSignature("timestamp=1"))
+	_, err = open.CreateSignatureContext(context.Background(), "timestamp=1")
+	assert.ErrorIs(t, err, sealed)
+	fake.SetError(nil)
+	assert.NotEmpty(t, open.CreateSignature("timestamp=1"))
+	assert.Empty(t, open.CreateSignature("timestamp=2"))
+}
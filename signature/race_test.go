@@ -0,0 +1,7 @@
+//go:build race
+
+package signature_test
+
+// Під race detector sync.Pool випадково відкидає елементи, тож перевірки
+// алокацій гарячого шляху вимикаються
+const raceEnabled = true
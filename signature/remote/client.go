@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+type (
+	ClientOptions struct {
+		TLSConfig *tls.Config   // сертифікат клієнта та CA сервера для mTLS
+		Token     string        // токен доступу, якщо mTLS не використовується
+		Timeout   time.Duration // таймаут одного запиту, за замовчуванням DefaultTimeout
+		// Обробник помилок CreateSignature та ValidateSignature, які повертають
+		// лише порожній підпис або false; nil - помилки доступні лише через *Context
+		OnError func(err error)
+	}
+	// Клієнт віддаленого підпису, реалізує signature.Sign для одного ключа
+	Client struct {
+		http      *http.Client
+		baseURL   string
+		key       string
+		apiKey    string
+		onError   func(error)
+		mutex     sync.RWMutex
+		token     string
+		destroyed bool
+	}
+)
+
+// address - "unix:///path/to/socket" або "https://host:port"
+func NewClient(address, key string, options ClientOptions) (*Client, error) {
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	transport := &http.Transport{TLSClientConfig: options.TLSConfig}
+	baseURL := strings.TrimRight(address, "/")
+	if socket, ok := strings.CutPrefix(address, "unix://"); ok {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		baseURL = "http://unix"
+	}
+	client := &Client{
+		http:    &http.Client{Transport: transport, Timeout: options.Timeout},
+		baseURL: baseURL,
+		key:     key,
+		token:   options.Token,
+		onError: options.OnError,
+	}
+	var response keyResponse
+	if err := client.call(context.Background(), http.MethodGet, "", nil, &response); err != nil {
+		return nil, err
+	}
+	client.apiKey = response.APIKey
+	return client, nil
+}
+
+// Функція для створення підпису з контекстом та помилкою
+func (client *Client) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	var response signResponse
+	if err := client.call(ctx, http.MethodPost, "/sign", signRequest{Message: queryString}, &response); err != nil {
+		return "", err
+	}
+	return response.Signature, nil
+}
+
+// Функція для валідації підпису з контекстом та помилкою
+func (client *Client) ValidateSignatureContext(ctx context.Context, message, signature string) (bool, error) {
+	var response verifyResponse
+	if err := client.call(ctx, http.MethodPost, "/verify", verifyRequest{Message: message, Signature: signature}, &response); err != nil {
+		return false, err
+	}
+	return response.Valid, nil
+}
+
+// Помилка віддаленого виклику дає порожній підпис та передається ClientOptions.OnError,
+// деталі - також у CreateSignatureContext
+func (client *Client) CreateSignature(queryString string) string {
+	signature, err := client.CreateSignatureContext(context.Background(), queryString)
+	client.report(err)
+	return signature
+}
+
+func (client *Client) ValidateSignature(message, signature string) bool {
+	valid, err := client.ValidateSignatureContext(context.Background(), message, signature)
+	client.report(err)
+	return err == nil && valid
+}
+
+func (client *Client) report(err error) {
+	if err != nil && client.onError != nil {
+		client.onError(err)
+	}
+}
+
+func (client *Client) GetAPIKey() string {
+	return client.apiKey
+}
+
+// Реалізація signature.Destroyer: клієнт відкидає токен доступу та закриває з'єднання,
+// подальші виклики повертають signature.ErrSignerDestroyed. Ключ лишається на сервері,
+// а сертифікат з ClientOptions.TLSConfig належить викликачу.
+func (client *Client) Destroy() {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.token = ""
+	client.destroyed = true
+	client.http.CloseIdleConnections()
+}
+
+func (client *Client) call(ctx context.Context, method, action string, request, response any) error {
+	client.mutex.RLock()
+	token, destroyed := client.token, client.destroyed
+	client.mutex.RUnlock()
+	if destroyed {
+		return signature.ErrSignerDestroyed
+	}
+	var body bytes.Buffer
+	if request != nil {
+		if err := json.NewEncoder(&body).Encode(request); err != nil {
+			return fmt.Errorf("error encoding request: %v", err)
+		}
+	}
+	endpoint := client.baseURL + "/v1/keys/" + url.PathEscape(client.key) + action
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer: %v", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(response)
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: %s", ErrForbidden, client.key)
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, client.key)
+	}
+	var failure errorResponse
+	json.NewDecoder(resp.Body).Decode(&failure)
+	switch failure.Code {
+	case codeApprovalPending:
+		return &signature.ApprovalPendingError{ID: failure.ApprovalID}
+	case codePolicyDenied:
+		return &signature.PolicyError{Rule: policyRule(failure.Rule), Detail: failure.Detail}
+	case codeSignerDestroyed:
+		return fmt.Errorf("remote signer: %w", signature.ErrSignerDestroyed)
+	}
+	return fmt.Errorf("remote signer: %s (status %d)", failure.Error, resp.StatusCode)
+}
+
+// Сентинел правила політики за текстом, щоб errors.Is працював і для віддалених відмов
+func policyRule(rule string) error {
+	for _, known := range []error{signature.ErrPolicyEndpoint, signature.ErrPolicySymbol, signature.ErrPolicyNotional,
+		signature.ErrPolicyAddress, signature.ErrPolicyQuota} {
+		if known.Error() == rule {
+			return known
+		}
+	}
+	return errors.New(rule)
+}
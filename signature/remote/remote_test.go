@@ -0,0 +1,229 @@
+package remote_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/remote"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+const message = "timestamp=1610612740000"
+
+func newKeyStore(t *testing.T) *signature.MemoryKeyStore {
+	keys := signature.NewMemoryKeyStore()
+	assert.Nil(t, keys.Put("binance", signature.Credential{Type: signature.CredentialHMAC, APIKey: "apy_key", SecretKey: "apy_secret"}))
+	assert.Nil(t, keys.Put("okx", signature.Credential{Type: signature.CredentialHMAC, APIKey: "okx_key", SecretKey: "okx_secret"}))
+	return keys
+}
+
+// Test 1: Unix socket with token authentication
+func TestRemoteUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.Nil(t, err)
+	defer listener.Close()
+	server := remote.NewServer(newKeyStore(t), remote.ServerOptions{
+		Tokens: map[string]string{"bot-token": "bot"},
+		ACL:    map[string][]string{"bot": {"binance"}},
+	})
+	go server.Serve(listener)
+
+	client, err := remote.NewClient("unix://"+socket, "binance", remote.ClientOptions{Token: "bot-token"})
+	assert.Nil(t, err)
+	var sign signature.Sign = client
+	assert.Equal(t, "apy_key", sign.GetAPIKey())
+	signed := sign.CreateSignature(message)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signed)
+	assert.True(t, sign.ValidateSignature(message, signed))
+	assert.False(t, sign.ValidateSignature(message, "wrong_signature"))
+
+	params := signature.NewParams().SetInt64("timestamp", 1610612740000)
+	params, err = signature.SignParams(params, sign)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{signed}, params.GetAll("signature"))
+	assert.True(t, signature.ValidateParams(params, sign))
+	// Наявне поле підпису не входить до канонічного рядка
+	params, err = signature.SignParams(params, sign)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{signed}, params.GetAll("signature"))
+
+	// Після Destroy клієнт не звертається до сервера
+	client.Destroy()
+	_, err = client.CreateSignatureContext(context.Background(), message)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+	_, err = signature.SignParams(params, client)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+
+	// Доступ до чужого ключа та невірний токен
+	_, err = remote.NewClient("unix://"+socket, "okx", remote.ClientOptions{Token: "bot-token"})
+	assert.ErrorIs(t, err, remote.ErrForbidden)
+	_, err = remote.NewClient("unix://"+socket, "binance", remote.ClientOptions{Token: "wrong-token"})
+	assert.ErrorIs(t, err, remote.ErrUnauthorized)
+}
+
+// Test 2: TCP with mutual TLS
+func TestRemoteMutualTLS(t *testing.T) {
+	ca, caKey := newCertificate(t, "ca", nil, nil)
+	serverCert, serverKey := newCertificate(t, "127.0.0.1", ca, caKey)
+	clientCert, clientKey := newCertificate(t, "bot", ca, caKey)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	server := httptest.NewUnstartedServer(remote.NewServer(newKeyStore(t), remote.ServerOptions{
+		ACL: map[string][]string{"bot": {"*"}},
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := remote.NewClient(server.URL, "okx", remote.ClientOptions{
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}},
+			RootCAs:      pool,
+		},
+		Timeout: time.Second,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "okx_key", client.GetAPIKey())
+	local := signature.NewSignHMAC("okx_key", "okx_secret")
+	assert.Equal(t, local.CreateSignature(message), client.CreateSignature(message))
+
+	// Без клієнтського сертифіката з'єднання відхиляється
+	_, err = remote.NewClient(server.URL, "okx", remote.ClientOptions{TLSConfig: &tls.Config{RootCAs: pool}})
+	assert.NotNil(t, err)
+}
+
+// Test 3: Transport errors and request body limit
+func TestRemoteErrors(t *testing.T) {
+	server := httptest.NewServer(remote.NewServer(newKeyStore(t), remote.ServerOptions{
+		Tokens:          map[string]string{"bot-token": "bot"},
+		ACL:             map[string][]string{"bot": {"*"}},
+		MaxRequestBytes: 64,
+	}))
+	var reported []error
+	client, err := remote.NewClient(server.URL, "binance", remote.ClientOptions{
+		Token:   "bot-token",
+		OnError: func(err error) { reported = append(reported, err) },
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, client.CreateSignature(message))
+	assert.Empty(t, reported)
+
+	// Завеликий запит відхиляється сервером
+	_, err = client.CreateSignatureContext(context.Background(), strings.Repeat("a", 128))
+	assert.ErrorContains(t, err, "status 413")
+
+	// Недоступний сервер: помилка передається OnError та повертається SignParams
+	server.Close()
+	assert.Empty(t, client.CreateSignature(message))
+	assert.Len(t, reported, 1)
+	assert.False(t, client.ValidateSignature(message, "signature"))
+	assert.Len(t, reported, 2)
+	params := signature.NewParams().Set("timestamp", "1610612740000")
+	_, err = signature.SignParams(params, client)
+	assert.ErrorContains(t, err, "remote signer")
+	assert.NotErrorIs(t, err, signature.ErrEmptySignature)
+	// Через Chain помилка транспорту не підміняється ErrEmptySignature
+	_, err = signature.SignParams(params, signature.Chain(client))
+	assert.ErrorContains(t, err, "remote signer")
+	assert.NotErrorIs(t, err, signature.ErrEmptySignature)
+}
+
+// Test 4: Signer failures reach the client with their reason
+func TestRemoteSignerFailures(t *testing.T) {
+	keys := signature.NewMemoryKeyStore()
+	signPolicy, err := signature.NewSignPolicy(signature.NewSignHMAC("apy_key", "apy_secret"), signature.Policy{Symbols: []string{"BTCUSDT"}})
+	assert.Nil(t, err)
+	keys.PutSigner("policy", signPolicy)
+	queue, err := signature.NewApprovalQueue(signature.NewSignHMAC("apy_key", "apy_secret"), signature.ApprovalConfig{
+		Approvers: map[string]ed25519.PublicKey{"alice": ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)},
+		Required:  1,
+	})
+	assert.Nil(t, err)
+	keys.PutSigner("approval", queue)
+	destroyed := signature.NewSignHMAC("apy_key", "apy_secret")
+	keys.PutSigner("destroyed", destroyed)
+	sealed := signaturetest.NewFakeSigner("apy_key")
+	keys.PutSigner("sealed", sealed)
+	server := httptest.NewServer(remote.NewServer(keys, remote.ServerOptions{
+		Tokens: map[string]string{"bot-token": "bot"},
+		ACL:    map[string][]string{"bot": {"*"}},
+	}))
+	defer server.Close()
+	client := func(name string) *remote.Client {
+		client, err := remote.NewClient(server.URL, name, remote.ClientOptions{Token: "bot-token"})
+		assert.Nil(t, err)
+		return client
+	}
+	destroyed.Destroy()
+	sealed.SetError(errors.New("vault is sealed"))
+
+	_, err = client("policy").CreateSignatureContext(context.Background(), "symbol=DOGEUSDT")
+	var denied *signature.PolicyError
+	assert.ErrorAs(t, err, &denied)
+	assert.ErrorIs(t, err, signature.ErrPolicySymbol)
+	assert.Equal(t, "DOGEUSDT", denied.Detail)
+
+	_, err = client("approval").CreateSignatureContext(context.Background(), message)
+	var pending *signature.ApprovalPendingError
+	if assert.ErrorAs(t, err, &pending) {
+		_, err = queue.Request(pending.ID)
+		assert.Nil(t, err)
+	}
+
+	_, err = client("destroyed").CreateSignatureContext(context.Background(), message)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+
+	params := signature.NewParams().Set("timestamp", "1610612740000")
+	_, err = signature.SignParams(params, client("sealed"))
+	assert.ErrorContains(t, err, "vault is sealed")
+	assert.ErrorContains(t, err, "status 502")
+}
+
+func newCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.Nil(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return certificate, key
+}
@@ -0,0 +1,193 @@
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+type (
+	ServerOptions struct {
+		// Токени доступу: токен -> ідентифікатор клієнта.
+		// Для mTLS ідентифікатором є CommonName сертифіката клієнта.
+		Tokens map[string]string
+		// Права доступу: ідентифікатор клієнта -> імена ключів, "*" - усі ключі
+		ACL map[string][]string
+		// Таймаут читання та запису запиту, за замовчуванням DefaultTimeout
+		Timeout time.Duration
+		// Максимальний розмір тіла запиту, за замовчуванням DefaultMaxRequestBytes
+		MaxRequestBytes int64
+	}
+	Server struct {
+		keys    signature.KeyStore
+		options ServerOptions
+		mux     *http.ServeMux
+	}
+)
+
+func NewServer(keys signature.KeyStore, options ServerOptions) *Server {
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.MaxRequestBytes == 0 {
+		options.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	server := &Server{keys: keys, options: options, mux: http.NewServeMux()}
+	server.mux.HandleFunc("GET /v1/keys/{name}", server.handleKey)
+	server.mux.HandleFunc("POST /v1/keys/{name}/sign", server.handleSign)
+	server.mux.HandleFunc("POST /v1/keys/{name}/verify", server.handleVerify)
+	return server
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+// Функція для обслуговування з'єднань на listener (unix сокет або TCP, в т.ч. tls.Listener)
+func (server *Server) Serve(listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:      http.TimeoutHandler(server, server.options.Timeout, `{"error":"timeout"}`),
+		ReadTimeout:  server.options.Timeout,
+		WriteTimeout: server.options.Timeout,
+	}
+	err := httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (server *Server) handleKey(w http.ResponseWriter, r *http.Request) {
+	sign, ok := server.authorize(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, keyResponse{APIKey: sign.GetAPIKey()})
+}
+
+func (server *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	sign, ok := server.authorize(w, r)
+	if !ok {
+		return
+	}
+	var request signRequest
+	if !server.decode(w, r, &request) {
+		return
+	}
+	signed, err := signature.CreateSignatureWith(r.Context(), sign, request.Message)
+	if err != nil {
+		status, response := signFailure(err)
+		writeJSON(w, status, response)
+		return
+	}
+	writeJSON(w, http.StatusOK, signResponse{Signature: signed})
+}
+
+// Статус та причина невдачі підписувача: очікування погодження - 202,
+// відмова політики - 422, знищений ключ - 503, інші помилки - 502
+func signFailure(err error) (int, errorResponse) {
+	var (
+		pending *signature.ApprovalPendingError
+		denied  *signature.PolicyError
+	)
+	response := errorResponse{Error: err.Error()}
+	switch {
+	case errors.As(err, &pending):
+		response.Code, response.ApprovalID = codeApprovalPending, pending.ID
+		return http.StatusAccepted, response
+	case errors.As(err, &denied):
+		response.Code, response.Detail = codePolicyDenied, denied.Detail
+		if denied.Rule != nil {
+			response.Rule = denied.Rule.Error()
+		}
+		return http.StatusUnprocessableEntity, response
+	case errors.Is(err, signature.ErrSignerDestroyed):
+		response.Code = codeSignerDestroyed
+		return http.StatusServiceUnavailable, response
+	}
+	response.Code = codeSignFailed
+	return http.StatusBadGateway, response
+}
+
+func (server *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	sign, ok := server.authorize(w, r)
+	if !ok {
+		return
+	}
+	var request verifyRequest
+	if !server.decode(w, r, &request) {
+		return
+	}
+	writeJSON(w, http.StatusOK, verifyResponse{Valid: sign.ValidateSignature(request.Message, request.Signature)})
+}
+
+// Функція для читання тіла запиту, розмір якого обмежено MaxRequestBytes
+func (server *Server) decode(w http.ResponseWriter, r *http.Request, request any) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, server.options.MaxRequestBytes)).Decode(request)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: "request too large"})
+		return false
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "bad request"})
+		return false
+	}
+	return true
+}
+
+// Функція для автентифікації клієнта, перевірки прав доступу та пошуку ключа
+func (server *Server) authorize(w http.ResponseWriter, r *http.Request) (signature.Sign, bool) {
+	identity, ok := server.identity(r)
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Error: ErrUnauthorized.Error()})
+		return nil, false
+	}
+	name := r.PathValue("name")
+	if !server.allowed(identity, name) {
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: ErrForbidden.Error()})
+		return nil, false
+	}
+	sign, err := server.keys.Signer(name)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: ErrKeyNotFound.Error()})
+		return nil, false
+	}
+	return sign, true
+}
+
+func (server *Server) identity(r *http.Request) (string, bool) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		for known, identity := range server.options.Tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+				return identity, true
+			}
+		}
+		return "", false
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return r.TLS.VerifiedChains[0][0].Subject.CommonName, true
+	}
+	return "", false
+}
+
+func (server *Server) allowed(identity, name string) bool {
+	for _, allowed := range server.options.ACL[identity] {
+		if allowed == "*" || allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,56 @@
+package remote
+
+import (
+	"errors"
+	"time"
+)
+
+// Віддалений підпис: сервер тримає ключі через звичайні підписувачі,
+// клієнт реалізує signature.Sign і пересилає запити на сервер.
+
+const (
+	DefaultTimeout = 5 * time.Second
+	// Максимальний розмір тіла запиту до сервера, за замовчуванням
+	DefaultMaxRequestBytes = 1 << 20
+)
+
+// Коди невдачі підпису у відповіді сервера
+const (
+	codeApprovalPending = "approval_pending"
+	codePolicyDenied    = "policy_denied"
+	codeSignerDestroyed = "signer_destroyed"
+	codeSignFailed      = "sign_failed"
+)
+
+var (
+	ErrUnauthorized = errors.New("remote signer: unauthorized")
+	ErrForbidden    = errors.New("remote signer: access to key is forbidden")
+	ErrKeyNotFound  = errors.New("remote signer: key not found")
+)
+
+type (
+	signRequest struct {
+		Message string `json:"message"`
+	}
+	signResponse struct {
+		Signature string `json:"signature"`
+	}
+	verifyRequest struct {
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+	verifyResponse struct {
+		Valid bool `json:"valid"`
+	}
+	keyResponse struct {
+		APIKey string `json:"api_key"`
+	}
+	// Code, Rule, Detail та ApprovalID передають типізовану причину невдачі підпису
+	errorResponse struct {
+		Error      string `json:"error"`
+		Code       string `json:"code,omitempty"`
+		Rule       string `json:"rule,omitempty"`
+		Detail     string `json:"detail,omitempty"`
+		ApprovalID string `json:"approval_id,omitempty"`
+	}
+)
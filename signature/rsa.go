@@ -1,17 +1,17 @@
 package signature
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/sha512"
 	"crypto/x509"
 	"encoding/base64"
-	"encoding/pem"
 	"errors"
-	"log"
-
-	"github.com/bitly/go-simplejson"
+	"io"
+	"sync"
 )
 
 // How can I use RSA API Keys?
@@ -22,55 +22,81 @@ import (
 
 // openssl rsa -in test-prv-key.pem -pubout -outform PEM -out test-pub-key.pem
 
-type SignRSA struct {
-	apiKey     string
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-}
+// Параметри PSS згідно з RFC 9421: сіль довжиною 64 байти, MGF1 з SHA-512
+var rsaPSSOptions = &rsa.PSSOptions{SaltLength: 64, Hash: crypto.SHA512}
+
+var ErrRSAPrivateKey = errors.New("rsa private key is not available")
+
+type (
+	SignRSA struct {
+		apiKey    string
+		private   *rsaPrivateKey // nil - підписувач лише для перевірки, спільний з клонами WithRand
+		publicKey *rsa.PublicKey
+		random    io.Reader // nil - crypto/rand.Reader
+	}
+	// Приватний ключ під RWMutex: підписи читають ключ паралельно, Destroy затирає його ексклюзивно
+	rsaPrivateKey struct {
+		mutex sync.RWMutex
+		key   *rsa.PrivateKey
+	}
+)
 
 // Функція для створення підпису RSA
 func (sign *SignRSA) CreateSignature(queryString string) string {
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
+}
+
+// Функція для створення підпису з помилкою, після Destroy - ErrSignerDestroyed
+func (sign *SignRSA) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
 	hashed := sha256.Sum256([]byte(queryString))
-	signature, err := rsa.SignPKCS1v15(rand.Reader, sign.privateKey, crypto.SHA256, hashed[:])
+	signature, err := sign.withPrivateKey(func(key *rsa.PrivateKey) ([]byte, error) {
+		return rsa.SignPKCS1v15(sign.rand(), key, crypto.SHA256, hashed[:])
+	})
 	if err != nil {
-		log.Fatalf("Error signing query string: %v", err)
+		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(signature)
-}
-
-func (sign *SignRSA) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
-	return signParameters(params, sign)
+	return base64.StdEncoding.EncodeToString(signature), nil
 }
 
-// Функція для валідації підпису
-func (sign *SignRSA) ValidateSignatureParams(params *simplejson.Json) bool {
-	signature, err := params.Get("signature").String()
+func (sign *SignRSA) ValidateSignature(message, signature string) bool {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
 	if err != nil {
 		return false
 	}
 
-	// Видалення підпису з параметрів
-	js, _ := params.MarshalJSON()
-	unsignedParams, _ := simplejson.NewJson(js)
-	unsignedParams.Del("signature")
+	hashed := sha256.Sum256([]byte(message))
+
+	err = rsa.VerifyPKCS1v15(sign.publicKey, crypto.SHA256, hashed[:], signatureBytes)
+	return err == nil
+}
 
-	message, err := ConvertSimpleJSONToString(unsignedParams)
+// Функція для створення підпису RSASSA-PSS з SHA-512 (rsa-pss-sha512 у RFC 9421)
+func (sign *SignRSA) CreateSignaturePSS(queryString string) string {
+	signature, _ := sign.createSignaturePSS(queryString)
+	return signature
+}
+
+func (sign *SignRSA) createSignaturePSS(queryString string) (string, error) {
+	hashed := sha512.Sum512([]byte(queryString))
+	signature, err := sign.withPrivateKey(func(key *rsa.PrivateKey) ([]byte, error) {
+		return rsa.SignPSS(sign.rand(), key, crypto.SHA512, hashed[:], rsaPSSOptions)
+	})
 	if err != nil {
-		return false
+		return "", err
 	}
-
-	return sign.ValidateSignature(message, signature)
+	return base64.StdEncoding.EncodeToString(signature), nil
 }
 
-func (sign *SignRSA) ValidateSignature(message, signature string) bool {
+func (sign *SignRSA) ValidateSignaturePSS(message, signature string) bool {
 	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
 	if err != nil {
 		return false
 	}
 
-	hashed := sha256.Sum256([]byte(message))
+	hashed := sha512.Sum512([]byte(message))
 
-	err = rsa.VerifyPKCS1v15(sign.publicKey, crypto.SHA256, hashed[:], signatureBytes)
+	err = rsa.VerifyPSS(sign.publicKey, crypto.SHA512, hashed[:], signatureBytes, rsaPSSOptions)
 	return err == nil
 }
 
@@ -78,6 +104,73 @@ func (sign *SignRSA) GetAPIKey() string {
 	return sign.apiKey
 }
 
+// Підпис приватним ключем під блокуванням читання, щоб Destroy не затер ключ посеред підпису
+func (sign *SignRSA) withPrivateKey(create func(key *rsa.PrivateKey) ([]byte, error)) ([]byte, error) {
+	if sign.private == nil {
+		return nil, ErrRSAPrivateKey
+	}
+	sign.private.mutex.RLock()
+	defer sign.private.mutex.RUnlock()
+	if sign.private.key == nil {
+		return nil, ErrSignerDestroyed
+	}
+	return create(sign.private.key)
+}
+
+// Функція для вилучення приватного ключа, після неї підписувач (і його клони WithRand)
+// лише перевіряє підписи. Затираються D, прості множники та CRT значення big.Int.
+// Обмеження: crypto/rsa тримає власні копії секретних значень у неекспортованих полях
+// PrecomputedValues, їх неможливо затерти - вони лишаються в пам'яті до збирання сміття.
+func (sign *SignRSA) Destroy() {
+	if sign.private == nil {
+		return
+	}
+	sign.private.mutex.Lock()
+	defer sign.private.mutex.Unlock()
+	if sign.private.key == nil {
+		return
+	}
+	for _, secret := range rsaPrivateValues(sign.private.key) {
+		munlock(bigIntBytes(secret))
+		wipeBigInt(secret)
+	}
+	sign.private.key = nil
+}
+
+// Функція для закріплення приватного ключа в оперативній пам'яті (mlock).
+// Як і для Destroy, внутрішні копії у PrecomputedValues не закріплюються.
+func (sign *SignRSA) Mlock() error {
+	if sign.private == nil {
+		return nil
+	}
+	sign.private.mutex.RLock()
+	defer sign.private.mutex.RUnlock()
+	if sign.private.key == nil {
+		return nil
+	}
+	for _, secret := range rsaPrivateValues(sign.private.key) {
+		if err := mlock(bigIntBytes(secret)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Функція для отримання підписувача з іншим джерелом випадковості (сіль PSS),
+// ключі спільні з вихідним підписувачем. Для детермінованих тестів.
+func (sign *SignRSA) WithRand(random io.Reader) *SignRSA {
+	clone := *sign
+	clone.random = random
+	return &clone
+}
+
+func (sign *SignRSA) rand() io.Reader {
+	if sign.random == nil {
+		return rand.Reader
+	}
+	return sign.random
+}
+
 func NewSignRSA(apiKey string, publicKey string, privateKey string) (sign *SignRSA, err error) {
 	private, err := loadRSAPrivateKeyFromPEM(privateKey)
 	if err != nil {
@@ -89,18 +182,27 @@ func NewSignRSA(apiKey string, publicKey string, privateKey string) (sign *SignR
 	}
 
 	sign = &SignRSA{
-		apiKey:     apiKey,
-		privateKey: private,
-		publicKey:  public,
+		apiKey:    apiKey,
+		private:   &rsaPrivateKey{key: private},
+		publicKey: public,
 	}
 	return
 }
 
+// Функція для створення підписувача лише для перевірки підписів, напр. ключа клієнта у HTTPVerifier
+func NewSignRSAPublic(apiKey string, publicKey string) (*SignRSA, error) {
+	public, err := loadRSAPublicKeyFromPEM(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SignRSA{apiKey: apiKey, publicKey: public}, nil
+}
+
 // Функція для завантаження приватного ключа з PEM рядка
 func loadRSAPrivateKeyFromPEM(content string) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(content))
-	if block == nil || block.Type != "PRIVATE KEY" {
-		return nil, errors.New("failed to decode PEM block containing private key")
+	block, err := decodePEMBlock(content, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
 	}
 
 	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
@@ -117,9 +219,9 @@ func loadRSAPrivateKeyFromPEM(content string) (*rsa.PrivateKey, error) {
 
 // Функція для завантаження публічного ключа з PEM рядка
 func loadRSAPublicKeyFromPEM(content string) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode([]byte(content))
-	if block == nil || block.Type != "PUBLIC KEY" {
-		return nil, errors.New("failed to decode PEM block containing public key")
+	block, err := decodePEMBlock(content, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
 	}
 
 	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
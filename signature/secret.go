@@ -0,0 +1,141 @@
+package signature
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"unsafe"
+)
+
+// Гігієна секретів: типи, що містять секрети, маскують їх при форматуванні
+// (fmt, %+v, %#v), серіалізації у JSON та логуванні через slog,
+// а підписувачі вміють затирати ключі (Destroy) та закріплювати їх у пам'яті (Mlock).
+
+const redacted = "[REDACTED]"
+
+var (
+	ErrMlockUnsupported = errors.New("mlock is not supported on this platform")
+	ErrSignerDestroyed  = errors.New("signer is destroyed")
+)
+
+// Підписувач, що вміє затирати свої секрети
+type Destroyer interface {
+	Destroy()
+}
+
+func (SecretKey) String() string   { return redacted }
+func (SecretKey) GoString() string { return redacted }
+func (SecretKey) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+func (SecretKey) MarshalJSON() ([]byte, error) { return json.Marshal(redacted) }
+func (SecretKey) LogValue() slog.Value         { return slog.StringValue(redacted) }
+
+func (credential Credential) String() string {
+	return fmt.Sprintf("Credential{Type: %s, APIKey: %s}", credential.Type, credential.APIKey)
+}
+func (credential Credential) GoString() string { return credential.String() }
+func (credential Credential) Format(f fmt.State, verb rune) {
+	io.WriteString(f, credential.String())
+}
+func (credential Credential) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSigner{Type: credential.Type, APIKey: credential.APIKey})
+}
+func (credential Credential) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", credential.Type), slog.String("api_key", credential.APIKey))
+}
+
+func (sign SignHMAC) String() string   { return "SignHMAC{apiKey: " + sign.apiKey + "}" }
+func (sign SignHMAC) GoString() string { return sign.String() }
+func (sign SignHMAC) Format(f fmt.State, verb rune) {
+	io.WriteString(f, sign.String())
+}
+func (sign SignHMAC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSigner{Type: CredentialHMAC, APIKey: sign.apiKey})
+}
+func (sign SignHMAC) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", CredentialHMAC), slog.String("api_key", sign.apiKey))
+}
+
+func (sign SignRSA) String() string   { return "SignRSA{apiKey: " + sign.apiKey + "}" }
+func (sign SignRSA) GoString() string { return sign.String() }
+func (sign SignRSA) Format(f fmt.State, verb rune) {
+	io.WriteString(f, sign.String())
+}
+func (sign SignRSA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSigner{Type: CredentialRSA, APIKey: sign.apiKey})
+}
+func (sign SignRSA) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", CredentialRSA), slog.String("api_key", sign.apiKey))
+}
+
+func (sign SignEd25519) String() string   { return "SignEd25519{apiKey: " + sign.apiKey + "}" }
+func (sign SignEd25519) GoString() string { return sign.String() }
+func (sign SignEd25519) Format(f fmt.State, verb rune) {
+	io.WriteString(f, sign.String())
+}
+func (sign SignEd25519) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedSigner{Type: CredentialEd25519, APIKey: sign.apiKey})
+}
+func (sign SignEd25519) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", CredentialEd25519), slog.String("api_key", sign.apiKey))
+}
+
+// Функція для затирання всіх ключів у наборі
+func (keyring *SignKeyring) Destroy() {
+	keyring.mutex.Lock()
+	defer keyring.mutex.Unlock()
+	for _, key := range keyring.keys {
+		if destroyer, ok := key.Sign.(Destroyer); ok {
+			destroyer.Destroy()
+		}
+	}
+}
+
+type redactedSigner struct {
+	Type   string `json:"type"`
+	APIKey string `json:"api_key"`
+}
+
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func wipeBigInt(n *big.Int) {
+	if n == nil {
+		return
+	}
+	words := n.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	n.SetInt64(0)
+}
+
+// Байтове представлення внутрішнього буфера big.Int, без копіювання
+func bigIntBytes(n *big.Int) []byte {
+	if n == nil {
+		return nil
+	}
+	words := n.Bits()
+	if len(words) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), len(words)*int(unsafe.Sizeof(words[0])))
+}
+
+// Секретні складові приватного ключа RSA
+func rsaPrivateValues(key *rsa.PrivateKey) []*big.Int {
+	values := []*big.Int{key.D, key.Precomputed.Dp, key.Precomputed.Dq, key.Precomputed.Qinv}
+	values = append(values, key.Primes...)
+	for _, crt := range key.Precomputed.CRTValues {
+		values = append(values, crt.Exp, crt.Coeff, crt.R)
+	}
+	return values
+}
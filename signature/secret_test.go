@@ -0,0 +1,126 @@
+package signature_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test 1: Secrets are redacted in fmt, JSON and slog output
+func TestSecretRedaction(t *testing.T) {
+	secret := signature.SecretKey("apy_secret")
+	sign := signature.NewSignHMAC("apy_key", secret)
+	credential := signature.Credential{Type: signature.CredentialHMAC, APIKey: "apy_key", SecretKey: "apy_secret"}
+	keyring, err := signature.NewSignKeyring(signature.KeyringKey{ID: "main", Sign: sign})
+	assert.Nil(t, err)
+
+	for _, value := range []any{secret, sign, *sign, credential, keyring} {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+			assert.NotContains(t, fmt.Sprintf(format, value), "apy_secret", format)
+		}
+		js, err := json.Marshal(value)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(js), "apy_secret")
+
+		var buffer bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buffer, nil)).Info("signer", "value", value)
+		assert.NotContains(t, buffer.String(), "apy_secret")
+	}
+	assert.Contains(t, fmt.Sprintf("%+v", sign), "apy_key")
+}
+
+// Test 2: Destroy wipes HMAC secret
+func TestDestroyHMAC(t *testing.T) {
+	secret := []byte("apy_secret")
+	sign := signature.NewSignHMACFromBytes("apy_key", secret)
+	message := "timestamp=1610612740000"
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", sign.CreateSignature(message))
+
+	var destroyer signature.Destroyer = sign
+	destroyer.Destroy()
+	assert.Equal(t, make([]byte, len(secret)), secret)
+	assert.Empty(t, sign.CreateSignature(message))
+	assert.False(t, sign.ValidateSignature(message, ""))
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+	assert.Nil(t, signed)
+}
+
+// Test 3: Destroy drops RSA and Ed25519 private keys but keeps verification
+func TestDestroyAsymmetric(t *testing.T) {
+	rsaSign, err := signature.NewSignRSA("apy_key", rsaTestPublicKey, rsaTestPrivateKey)
+	assert.Nil(t, err)
+	ed25519Sign, err := signature.NewSignEd25519("apy_key", rfc9421Ed25519PublicKey, rfc9421Ed25519PrivateKey)
+	assert.Nil(t, err)
+	message := "timestamp=1610612740000"
+	for _, sign := range []interface {
+		signature.SignJSON
+		signature.Destroyer
+	}{rsaSign, ed25519Sign} {
+		signed := sign.CreateSignature(message)
+		sign.Destroy()
+		assert.Empty(t, sign.CreateSignature(message))
+		assert.True(t, sign.ValidateSignature(message, signed))
+		params := simplejson.New()
+		params.Set("timestamp", 1610612740000)
+		_, err := sign.SignParameters(params)
+		assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+		sign.Destroy()
+	}
+
+	// Клони з WithRand та WithOptions ділять ключ з вихідним підписувачем
+	_, err = rsaSign.WithRand(nil).CreateSignatureContext(context.Background(), message)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+	_, err = ed25519Sign.WithOptions(signature.Ed25519ctxOptions("turbo-signer")).CreateSignatureContext(context.Background(), message)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+}
+
+// Test 4: Mlock secrets
+func TestMlock(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	if err := sign.Mlock(); err != nil {
+		t.Skipf("mlock is not available: %v", err)
+	}
+	sign.Destroy()
+}
+
+// Test 5: Destroy during concurrent HMAC, RSA and Ed25519 signing (go test -race)
+func TestDestroyConcurrent(t *testing.T) {
+	rsaSign, err := signature.NewSignRSA("apy_key", rsaTestPublicKey, rsaTestPrivateKey)
+	assert.Nil(t, err)
+	ed25519Sign, err := signature.NewSignEd25519("apy_key", rfc9421Ed25519PublicKey, rfc9421Ed25519PrivateKey)
+	assert.Nil(t, err)
+	message := "timestamp=1610612740000"
+	for _, sign := range []interface {
+		signature.Sign
+		signature.Destroyer
+	}{signature.NewSignHMAC("apy_key", "apy_secret"), rsaSign, ed25519Sign} {
+		expected := sign.CreateSignature(message)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					// Підпис або справжній, або порожній - ніколи не від затертого ключа
+					if signed := sign.CreateSignature(message); signed != "" {
+						assert.Equal(t, expected, signed)
+					}
+				}
+			}()
+		}
+		sign.Destroy()
+		wg.Wait()
+		assert.Empty(t, sign.CreateSignature(message))
+	}
+}
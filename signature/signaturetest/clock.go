@@ -0,0 +1,40 @@
+package signaturetest
+
+import (
+	"sync"
+	"time"
+)
+
+// Керований годинник для компонентів з SetClock:
+//
+//	clock := signaturetest.NewClock(time.Unix(1610612740, 0))
+//	policy.SetClock(clock.Now)
+//	clock.Advance(time.Minute)
+type Clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+func (clock *Clock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *Clock) Set(now time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = now
+}
+
+// Функція для зсуву годинника, повертає новий час
+func (clock *Clock) Advance(d time.Duration) time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = clock.now.Add(d)
+	return clock.now
+}
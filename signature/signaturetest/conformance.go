@@ -0,0 +1,216 @@
+package signaturetest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+// Фабрика підписувача для перевірок, викликається для кожної підперевірки.
+// Пари ключів, що створюються фабрикою, мають бути узгодженими:
+// підписувач перевіряє власні підписи.
+type Factory func(t *testing.T) signature.Sign
+
+// Функція для запуску набору перевірок відповідності реалізації Sign. Підпис параметрів
+// перевіряється через SignJSON підписувача або адаптер signature.JSON.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, signature.JSON(factory(t))) })
+	t.Run("Tamper", func(t *testing.T) { testTamper(t, signature.JSON(factory(t))) })
+	t.Run("SignatureField", func(t *testing.T) { testSignatureField(t, signature.JSON(factory(t))) })
+	t.Run("Concurrency", func(t *testing.T) { testConcurrency(t, signature.JSON(factory(t))) })
+	t.Run("NilParams", func(t *testing.T) { testNilParams(t, signature.JSON(factory(t))) })
+	t.Run("EmptyParams", func(t *testing.T) { testRoundTripParams(t, signature.JSON(factory(t)), simplejson.New()) })
+	t.Run("UnicodeParams", func(t *testing.T) { testRoundTripParams(t, signature.JSON(factory(t)), unicodeParams()) })
+	t.Run("LargeParams", func(t *testing.T) { testRoundTripParams(t, signature.JSON(factory(t)), largeParams()) })
+}
+
+func orderParams() *simplejson.Json {
+	params := simplejson.New()
+	params.Set("symbol", "BTCUSDT")
+	params.Set("side", "BUY")
+	params.Set("type", "LIMIT")
+	params.Set("quantity", "0.01")
+	params.Set("price", "65000.5")
+	params.Set("timestamp", int64(1610612740000))
+	return params
+}
+
+func unicodeParams() *simplejson.Json {
+	params := simplejson.New()
+	params.Set("memo", "Привіт, світе! 你好 🚀")
+	params.Set("ключ", "значення")
+	params.Set("special", "a&b=c d+e%f/g?h#i")
+	params.Set("timestamp", int64(1610612740000))
+	return params
+}
+
+func largeParams() *simplejson.Json {
+	params := simplejson.New()
+	for i := 0; i < 1000; i++ {
+		params.Set(fmt.Sprintf("key%04d", i), fmt.Sprintf("value%d", i))
+	}
+	params.Set("blob", strings.Repeat("x", 64*1024))
+	return params
+}
+
+// Канонічний рядок параметрів без поля підпису
+func canonical(params *simplejson.Json) string {
+	return signature.ParamsFromSimpleJSON(params).Del("signature").Encode()
+}
+
+// Підпис параметрів з перевіркою результату, nil - якщо підписати не вдалося
+func signParams(t *testing.T, sign signature.SignJSON, params *simplejson.Json) *simplejson.Json {
+	t.Helper()
+	before, err := params.Encode()
+	assert.Nil(t, err)
+	signed, err := sign.SignParameters(params)
+	if !assert.Nil(t, err) || !assert.NotNil(t, signed) {
+		return nil
+	}
+	after, err := params.Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, string(before), string(after), "SignParameters must not modify its input")
+	value, err := signed.Get("signature").String()
+	if !assert.Nil(t, err, "signature must be a string") || !assert.NotEmpty(t, value) {
+		return nil
+	}
+	for key, value := range params.MustMap() {
+		if key != "signature" {
+			assert.Equal(t, value, signed.Get(key).Interface(), "parameter %s is changed", key)
+		}
+	}
+	return signed
+}
+
+func testRoundTrip(t *testing.T, sign signature.SignJSON) {
+	testRoundTripParams(t, sign, orderParams())
+	message := canonical(orderParams())
+	created := sign.CreateSignature(message)
+	assert.NotEmpty(t, created)
+	assert.True(t, sign.ValidateSignature(message, created))
+	assert.False(t, sign.ValidateSignature(message+"&extra=1", created))
+}
+
+func testRoundTripParams(t *testing.T, sign signature.SignJSON, params *simplejson.Json) {
+	signed := signParams(t, sign, params)
+	if signed == nil {
+		return
+	}
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.True(t, sign.ValidateSignature(canonical(params), signed.Get("signature").MustString()),
+		"SignParameters must sign the canonical string")
+}
+
+func testTamper(t *testing.T, sign signature.SignJSON) {
+	signed := signParams(t, sign, orderParams())
+	if signed == nil {
+		return
+	}
+	value := signed.Get("signature").MustString()
+	tamper := map[string]func(params *simplejson.Json){
+		"changed value":   func(params *simplejson.Json) { params.Set("quantity", "0.02") },
+		"added param":     func(params *simplejson.Json) { params.Set("recvWindow", 5000) },
+		"removed param":   func(params *simplejson.Json) { params.Del("price") },
+		"renamed param":   func(params *simplejson.Json) { params.Del("side"); params.Set("sidE", "BUY") },
+		"changed type":    func(params *simplejson.Json) { params.Set("timestamp", "1610612740001") },
+		"wrong signature": func(params *simplejson.Json) { params.Set("signature", "wrong_signature") },
+		"flipped signature": func(params *simplejson.Json) {
+			flipped := []byte(value)
+			if flipped[0] == 'A' {
+				flipped[0] = 'B'
+			} else {
+				flipped[0] = 'A'
+			}
+			params.Set("signature", string(flipped))
+		},
+		"truncated signature": func(params *simplejson.Json) { params.Set("signature", value[:len(value)/2]) },
+		"empty signature":     func(params *simplejson.Json) { params.Set("signature", "") },
+	}
+	for name, change := range tamper {
+		tampered := copyParams(signed)
+		change(tampered)
+		assert.False(t, sign.ValidateSignatureParams(tampered), name)
+	}
+	message := canonical(signed)
+	assert.False(t, sign.ValidateSignature(message, ""))
+	assert.False(t, sign.ValidateSignature(message, "not base64 or hex!"))
+}
+
+func testSignatureField(t *testing.T, sign signature.SignJSON) {
+	params := orderParams()
+	assert.False(t, sign.ValidateSignatureParams(params), "params without signature")
+
+	// Підпис, що не є рядком
+	for _, value := range []any{12345, true, nil, []any{"a"}, map[string]any{"a": "b"}} {
+		invalid := orderParams()
+		invalid.Set("signature", value)
+		assert.False(t, sign.ValidateSignatureParams(invalid), "signature %v", value)
+	}
+
+	// Наявне поле підпису не входить до канонічного рядка і замінюється
+	signed := signParams(t, sign, orderParams())
+	if signed == nil {
+		return
+	}
+	stale := orderParams()
+	stale.Set("signature", "stale")
+	resigned := signParams(t, sign, stale)
+	if resigned == nil {
+		return
+	}
+	assert.NotEqual(t, "stale", resigned.Get("signature").MustString())
+	assert.True(t, sign.ValidateSignatureParams(resigned))
+	twice := signParams(t, sign, signed)
+	if twice != nil {
+		assert.True(t, sign.ValidateSignatureParams(twice))
+	}
+}
+
+// nil та не-об'єкти повертають помилку, а не панікують чи підписують порожній рядок
+func testNilParams(t *testing.T, sign signature.SignJSON) {
+	_, err := sign.SignParameters(nil)
+	assert.NotNil(t, err)
+	assert.False(t, sign.ValidateSignatureParams(nil))
+	list, err := simplejson.NewJson([]byte(`["timestamp",1610612740000]`))
+	assert.Nil(t, err)
+	_, err = sign.SignParameters(list)
+	assert.NotNil(t, err)
+	assert.False(t, sign.ValidateSignatureParams(list))
+}
+
+func testConcurrency(t *testing.T, sign signature.SignJSON) {
+	const goroutines, iterations = 8, 16
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				params := orderParams()
+				params.Set("clientOrderId", fmt.Sprintf("%d-%d", g, i))
+				signed, err := sign.SignParameters(params)
+				if !assert.Nil(t, err) {
+					return
+				}
+				assert.True(t, sign.ValidateSignatureParams(signed))
+				message := canonical(params)
+				assert.True(t, sign.ValidateSignature(message, sign.CreateSignature(message)))
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func copyParams(params *simplejson.Json) *simplejson.Json {
+	copied := simplejson.New()
+	for key, value := range params.MustMap() {
+		copied.Set(key, value)
+	}
+	return copied
+}
@@ -0,0 +1,45 @@
+package signaturetest_test
+
+import (
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+)
+
+// Test 1: SignHMAC conformance
+func TestConformanceHMAC(t *testing.T) {
+	signaturetest.RunConformance(t, func(t *testing.T) signature.Sign {
+		return signaturetest.NewHMAC()
+	})
+}
+
+// Test 2: SignRSA conformance
+func TestConformanceRSA(t *testing.T) {
+	signaturetest.RunConformance(t, func(t *testing.T) signature.Sign {
+		return signaturetest.NewRSA(t)
+	})
+}
+
+// Test 3: SignEd25519 conformance
+func TestConformanceEd25519(t *testing.T) {
+	signaturetest.RunConformance(t, func(t *testing.T) signature.Sign {
+		return signaturetest.NewEd25519(t)
+	})
+}
+
+// Test 4: Middleware chain conformance
+func TestConformanceChain(t *testing.T) {
+	signaturetest.RunConformance(t, func(t *testing.T) signature.Sign {
+		return signature.Chain(signaturetest.NewHMAC(), signature.Hooks{
+			AfterSign: func(message, signature string, err error) {},
+		}.Middleware())
+	})
+}
+
+// Test 5: FakeSigner conformance
+func TestConformanceFake(t *testing.T) {
+	signaturetest.RunConformance(t, func(t *testing.T) signature.Sign {
+		return signaturetest.NewFakeSigner("fake_key")
+	})
+}
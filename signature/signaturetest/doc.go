@@ -0,0 +1,4 @@
+// Пакет signaturetest містить набір перевірок відповідності для власних
+// реалізацій signature.Sign (віддалених, HSM тощо): RunConformance перевіряє,
+// що реалізація поводиться так само, як вбудовані SignHMAC, SignRSA та SignEd25519.
+package signaturetest
@@ -0,0 +1,143 @@
+package signaturetest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+// Фальшивий підписувач для модульних тестів споживачів: підпис - hex SHA-256
+// API ключа та повідомлення, кожен виклик записується.
+type (
+	FakeSigner struct {
+		apiKey string
+		mutex  sync.Mutex
+		calls  []Call
+		err    error
+	}
+	Call struct {
+		Method    string // назва методу Sign
+		Message   string // канонічний рядок або повідомлення
+		Signature string // створений або перевірений підпис
+		Valid     bool   // результат перевірки
+	}
+)
+
+func NewFakeSigner(apiKey string) *FakeSigner {
+	return &FakeSigner{apiKey: apiKey}
+}
+
+// Функція для імітації збою: CreateSignature повертає порожній рядок,
+// CreateSignatureContext та SignParameters - err. nil відновлює роботу.
+func (sign *FakeSigner) SetError(err error) {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.err = err
+}
+
+// Функція для отримання копії записаних викликів
+func (sign *FakeSigner) Calls() []Call {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	return append([]Call(nil), sign.calls...)
+}
+
+func (sign *FakeSigner) Reset() {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.calls = nil
+}
+
+// Підпис, який FakeSigner створює для повідомлення
+func (sign *FakeSigner) Expected(message string) string {
+	digest := sha256.Sum256([]byte(sign.apiKey + "\n" + message))
+	return hex.EncodeToString(digest[:])
+}
+
+func (sign *FakeSigner) CreateSignature(queryString string) string {
+	signature := ""
+	if sign.failure() == nil {
+		signature = sign.Expected(queryString)
+	}
+	sign.record(Call{Method: "CreateSignature", Message: queryString, Signature: signature})
+	return signature
+}
+
+func (sign *FakeSigner) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	if err := sign.failure(); err != nil {
+		sign.record(Call{Method: "CreateSignature", Message: queryString})
+		return "", err
+	}
+	return sign.CreateSignature(queryString), nil
+}
+
+func (sign *FakeSigner) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	if params == nil {
+		return nil, signature.ErrNilParams
+	}
+	message := canonical(params)
+	if err := sign.failure(); err != nil {
+		sign.record(Call{Method: "SignParameters", Message: message})
+		return nil, err
+	}
+	signed, err := signature.SignParametersWith(params, signParametersOnly{sign})
+	if err == nil {
+		sign.record(Call{Method: "SignParameters", Message: message, Signature: signed.Get("signature").MustString()})
+	}
+	return signed, err
+}
+
+func (sign *FakeSigner) ValidateSignatureParams(params *simplejson.Json) bool {
+	if params == nil {
+		sign.record(Call{Method: "ValidateSignatureParams"})
+		return false
+	}
+	value, _ := params.Get("signature").String()
+	valid := signature.ValidateSignatureParamsWith(params, signParametersOnly{sign})
+	sign.record(Call{Method: "ValidateSignatureParams", Message: canonical(params), Signature: value, Valid: valid})
+	return valid
+}
+
+func (sign *FakeSigner) ValidateSignature(message, signature string) bool {
+	valid := signature != "" && signature == sign.Expected(message)
+	sign.record(Call{Method: "ValidateSignature", Message: message, Signature: signature, Valid: valid})
+	return valid
+}
+
+func (sign *FakeSigner) GetAPIKey() string {
+	return sign.apiKey
+}
+
+func (sign *FakeSigner) failure() error {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	return sign.err
+}
+
+func (sign *FakeSigner) record(call Call) {
+	sign.mutex.Lock()
+	defer sign.mutex.Unlock()
+	sign.calls = append(sign.calls, call)
+}
+
+// Підписувач для SignParametersWith та ValidateSignatureParamsWith без запису
+// вкладених викликів CreateSignature та ValidateSignature
+type signParametersOnly struct {
+	*FakeSigner
+}
+
+func (sign signParametersOnly) CreateSignature(queryString string) string {
+	return sign.Expected(queryString)
+}
+
+func (sign signParametersOnly) ValidateSignature(message, signature string) bool {
+	return signature != "" && signature == sign.Expected(message)
+}
+
+func (sign signParametersOnly) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	return sign.Expected(queryString), nil
+}
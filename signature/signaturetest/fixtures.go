@@ -0,0 +1,58 @@
+package signaturetest
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+// Тестові пари ключів для кожного алгоритму. Ключі публічні, лише для тестів!
+
+const (
+	FixtureAPIKey     = "apy_key"
+	FixtureHMACSecret = "apy_secret"
+)
+
+var (
+	//go:embed testdata/rsa-public.pem
+	RSAPublicKeyPEM string
+	//go:embed testdata/rsa-private.pem
+	RSAPrivateKeyPEM string
+	//go:embed testdata/ed25519-public.pem
+	Ed25519PublicKeyPEM string
+	//go:embed testdata/ed25519-private.pem
+	Ed25519PrivateKeyPEM string
+)
+
+func NewHMAC() *signature.SignHMAC {
+	return signature.NewSignHMAC(FixtureAPIKey, FixtureHMACSecret)
+}
+
+func NewRSA(t testing.TB) *signature.SignRSA {
+	t.Helper()
+	sign, err := signature.NewSignRSA(FixtureAPIKey, RSAPublicKeyPEM, RSAPrivateKeyPEM)
+	if err != nil {
+		t.Fatalf("loading RSA fixture: %v", err)
+	}
+	return sign
+}
+
+func NewEd25519(t testing.TB) *signature.SignEd25519 {
+	t.Helper()
+	sign, err := signature.NewSignEd25519(FixtureAPIKey, Ed25519PublicKeyPEM, Ed25519PrivateKeyPEM)
+	if err != nil {
+		t.Fatalf("loading Ed25519 fixture: %v", err)
+	}
+	return sign
+}
+
+// Підписувачі на тестових ключах за назвою алгоритму (signature.Algorithm)
+func Signers(t testing.TB) map[string]signature.Sign {
+	t.Helper()
+	signers := map[string]signature.Sign{}
+	for _, sign := range []signature.Sign{NewHMAC(), NewRSA(t), NewEd25519(t)} {
+		signers[signature.Algorithm(sign)] = sign
+	}
+	return signers
+}
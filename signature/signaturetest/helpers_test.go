@@ -0,0 +1,101 @@
+package signaturetest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test 6: Fixture signers for every algorithm
+func TestFixtures(t *testing.T) {
+	signers := signaturetest.Signers(t)
+	assert.Len(t, signers, 3)
+	for _, algorithm := range []string{"hmac-sha256", "rsa-v1_5-sha256", "ed25519"} {
+		sign, ok := signers[algorithm]
+		if assert.True(t, ok, algorithm) {
+			assert.Equal(t, signaturetest.FixtureAPIKey, sign.GetAPIKey())
+		}
+	}
+	message := "timestamp=1610612740000"
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signers["hmac-sha256"].CreateSignature(message))
+}
+
+// Test 7: Clock drives components with SetClock
+func TestClock(t *testing.T) {
+	start := time.Date(2021, 1, 14, 8, 25, 40, 0, time.UTC)
+	clock := signaturetest.NewClock(start)
+	assert.Equal(t, start, clock.Now())
+	assert.Equal(t, start.Add(time.Minute), clock.Advance(time.Minute))
+	clock.Set(start)
+
+	keyring, err := signature.NewSignKeyring(signature.KeyringKey{ID: "old", Sign: signaturetest.NewHMAC()})
+	assert.Nil(t, err)
+	keyring.SetClock(clock.Now)
+	next := signature.NewSignHMAC("apy_key", "next_secret")
+	assert.Nil(t, keyring.AddKey(signature.KeyringKey{ID: "next", Sign: next, NotBefore: start.Add(time.Hour)}))
+	assert.ErrorIs(t, keyring.Promote("next"), signature.ErrKeyNotValid)
+	clock.Advance(time.Hour)
+	assert.Nil(t, keyring.Promote("next"))
+
+	tracer := signature.NewMemoryTracer()
+	tracer.SetClock(clock.Now)
+	_, span := tracer.Start(context.Background(), "span")
+	clock.Advance(time.Second)
+	span.End()
+	assert.Equal(t, time.Second, tracer.Spans()[0].End.Sub(tracer.Spans()[0].Start))
+}
+
+// Test 8: Deterministic entropy makes RSA-PSS reproducible
+func TestDeterministicReader(t *testing.T) {
+	first, second := make([]byte, 100), make([]byte, 100)
+	_, err := io.ReadFull(signaturetest.NewDeterministicReader("seed"), first)
+	assert.Nil(t, err)
+	_, err = io.ReadFull(signaturetest.NewDeterministicReader("seed"), second)
+	assert.Nil(t, err)
+	assert.Equal(t, first, second)
+	_, err = io.ReadFull(signaturetest.NewDeterministicReader("other"), second)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, second)
+
+	sign := signaturetest.NewRSA(t)
+	message := "timestamp=1610612740000"
+	pss := sign.WithRand(signaturetest.NewDeterministicReader("seed")).CreateSignaturePSS(message)
+	assert.Equal(t, pss, sign.WithRand(signaturetest.NewDeterministicReader("seed")).CreateSignaturePSS(message))
+	assert.NotEqual(t, pss, sign.CreateSignaturePSS(message))
+	assert.True(t, sign.ValidateSignaturePSS(message, pss))
+}
+
+// Test 9: FakeSigner records calls and simulates failures
+func TestFakeSigner(t *testing.T) {
+	sign := signaturetest.NewFakeSigner("fake_key")
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	expected := sign.Expected("timestamp=1610612740000")
+	assert.Equal(t, expected, signed.Get("signature").MustString())
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.False(t, sign.ValidateSignature("timestamp=1", expected))
+	assert.Equal(t, []signaturetest.Call{
+		{Method: "SignParameters", Message: "timestamp=1610612740000", Signature: expected},
+		{Method: "ValidateSignatureParams", Message: "timestamp=1610612740000", Signature: expected, Valid: true},
+		{Method: "ValidateSignature", Message: "timestamp=1", Signature: expected},
+	}, sign.Calls())
+
+	sign.Reset()
+	failure := errors.New("backend is down")
+	sign.SetError(failure)
+	_, err = sign.SignParameters(params)
+	assert.ErrorIs(t, err, failure)
+	assert.Empty(t, sign.CreateSignature("timestamp=1610612740000"))
+	assert.Len(t, sign.Calls(), 2)
+	sign.SetError(nil)
+	assert.Equal(t, expected, sign.CreateSignature("timestamp=1610612740000"))
+}
@@ -0,0 +1,40 @@
+package signaturetest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// Детерміноване джерело випадковості: SHA-256(seed || лічильник).
+// Для SignRSA.WithRand, Ed25519BatchVerifier.Rand тощо, лише для тестів!
+type deterministicReader struct {
+	mutex   sync.Mutex
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+func NewDeterministicReader(seed string) io.Reader {
+	return &deterministicReader{seed: []byte(seed)}
+}
+
+func (reader *deterministicReader) Read(p []byte) (int, error) {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+	n := 0
+	for n < len(p) {
+		if len(reader.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], reader.counter)
+			reader.counter++
+			block := sha256.Sum256(append(append([]byte(nil), reader.seed...), counter[:]...))
+			reader.block = block[:]
+		}
+		copied := copy(p[n:], reader.block)
+		reader.block = reader.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
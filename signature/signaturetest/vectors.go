@@ -0,0 +1,38 @@
+package signaturetest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature/vectors"
+)
+
+// Функція для запуску еталонних векторів з файлів (шаблони filepath.Glob),
+// кожен вектор - окрема підперевірка з назвою файлу та вектора
+func RunVectors(t *testing.T, patterns ...string) {
+	t.Helper()
+	found := 0
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("bad pattern %q: %v", pattern, err)
+		}
+		for _, path := range paths {
+			loaded, err := vectors.Load(path)
+			if err != nil {
+				t.Fatalf("loading vectors: %v", err)
+			}
+			for _, vector := range loaded {
+				found++
+				t.Run(filepath.Base(path)+"/"+vector.Name, func(t *testing.T) {
+					if err := vector.Verify(); err != nil {
+						t.Error(err)
+					}
+				})
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatalf("no test vectors found in %v", patterns)
+	}
+}
@@ -0,0 +1,12 @@
+package signaturetest_test
+
+import (
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+)
+
+// Test 10: Golden vectors of built-in signers
+func TestGoldenVectors(t *testing.T) {
+	signaturetest.RunVectors(t, "testdata/vectors/*.json", "testdata/vectors/*.yaml")
+}
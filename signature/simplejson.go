@@ -0,0 +1,324 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/bitly/go-simplejson"
+)
+
+// Адаптер simplejson. Ядро пакета - Sign, Params, канонізація та обгортки - simplejson
+// не використовує: параметри simplejson перетворюються на map і підписуються тим самим
+// канонічним рядком через CreateSignature. SignParameters та ValidateSignatureParams
+// підписувачів цього пакета зібрані тут і зберігаються для сумісності, будь-який
+// інший Sign отримує їх через JSON.
+
+// Sign з підписом та перевіркою параметрів simplejson
+type SignJSON interface {
+	Sign
+	SignParameters(params *simplejson.Json) (*simplejson.Json, error)
+	ValidateSignatureParams(params *simplejson.Json) bool
+}
+
+type jsonSign struct {
+	Sign
+}
+
+// Функція для отримання SignJSON з довільного Sign: SignJSON повертається без змін,
+// інший Sign обгортається адаптером
+func JSON(sign Sign) SignJSON {
+	if signer, ok := sign.(SignJSON); ok {
+		return signer
+	}
+	return jsonSign{Sign: sign}
+}
+
+// Причина невдачі підпису обгорнутого Sign лишається доступною через SignContext
+func (sign jsonSign) CreateSignatureContext(ctx context.Context, queryString string) (string, error) {
+	return createSignature(ctx, sign.Sign, queryString)
+}
+
+func (sign jsonSign) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	return signParameters(params, sign.Sign)
+}
+
+func (sign jsonSign) ValidateSignatureParams(params *simplejson.Json) bool {
+	return validateSignatureParams(params, sign.Sign)
+}
+
+// Канонічний рядок параметрів simplejson. Значення форматуються як fmt %v, зокрема
+// масиви - [a 1], а не масивом JSON, як зрізи у StructParams та Params.SetList
+func ConvertSimpleJSONToString(js *simplejson.Json) (string, error) {
+	values, err := paramsMap(js)
+	if err != nil {
+		return "", err
+	}
+	return CanonicalString(values), nil
+}
+
+// Функція для створення Params з simplejson, значення форматуються як у ConvertSimpleJSONToString
+func ParamsFromSimpleJSON(js *simplejson.Json) *Params {
+	if js == nil {
+		return NewParams()
+	}
+	return ParamsFromMap(js.MustMap())
+}
+
+// Функція для розбору параметрів з JSON об'єкта. На відміну від simplejson.NewJson
+// відхиляє повтори ключа ({"a":1,"a":2}) на будь-якій глибині, значення яких різні
+// парсери обирають по-різному, та дані після об'єкта. Числа зберігаються як json.Number.
+func ParseParams(data []byte) (*simplejson.Json, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, ErrParamsNotObject
+	}
+	values, err := decodeParamsObject(decoder, 1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after params object")
+	}
+	params := simplejson.New()
+	for key, value := range values {
+		params.Set(key, value)
+	}
+	return params, nil
+}
+
+// Параметри як map: nil та не-об'єкти (масив, рядок) відхиляються,
+// а не підписуються як порожній канонічний рядок
+func paramsMap(js *simplejson.Json) (map[string]any, error) {
+	if js == nil {
+		return nil, ErrNilParams
+	}
+	values, err := js.Map()
+	if err != nil {
+		return nil, ErrParamsNotObject
+	}
+	return values, nil
+}
+
+func signParameters(params *simplejson.Json, sign Sign) (*simplejson.Json, error) {
+	values, err := paramsMap(params)
+	if err != nil {
+		return nil, err
+	}
+	// Створення підпису, наявне поле підпису не входить до канонічного рядка і замінюється новим
+	signature, err := createSignature(context.Background(), sign, canonicalString(values, "signature"))
+	if err != nil {
+		return nil, err
+	}
+	return signedCopy(values, signature), nil
+}
+
+// Поверхнева копія параметрів замість серіалізації та повторного розбору JSON,
+// вхідні параметри лишаються без змін
+func signedCopy(values map[string]any, signature string) *simplejson.Json {
+	signedParams := simplejson.New()
+	for key, value := range values {
+		signedParams.Set(key, value)
+	}
+	signedParams.Set("signature", signature)
+	return signedParams
+}
+
+func validateSignatureParams(params *simplejson.Json, sign Sign) bool {
+	values, err := paramsMap(params)
+	if err != nil {
+		return false
+	}
+	// Підпис лише рядком: числа, масиви, null тощо не приводяться до рядка
+	signature, ok := values["signature"].(string)
+	if !ok {
+		return false
+	}
+	// Канонічний рядок без поля підпису, без копіювання параметрів
+	return sign.ValidateSignature(canonicalString(values, "signature"), signature)
+}
+
+// Функція для підпису параметрів довільним Sign без звернення до його SignParameters,
+// для реалізацій SignJSON поза цим пакетом
+func SignParametersWith(params *simplejson.Json, sign Sign) (*simplejson.Json, error) {
+	return signParameters(params, sign)
+}
+
+// Функція для валідації підписаних параметрів довільним Sign
+func ValidateSignatureParamsWith(params *simplejson.Json, sign Sign) bool {
+	return validateSignatureParams(params, sign)
+}
+
+func (sign *SignHMAC) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	return signParameters(params, sign)
+}
+
+func (sign *SignHMAC) ValidateSignatureParams(params *simplejson.Json) bool {
+	return validateSignatureParams(params, sign)
+}
+
+func (sign *SignEd25519) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	return signParameters(params, sign)
+}
+
+// Функція для валідації підпису
+func (sign *SignEd25519) ValidateSignatureParams(params *simplejson.Json) bool {
+	return validateSignatureParams(params, sign)
+}
+
+func (sign *SignRSA) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	return signParameters(params, sign)
+}
+
+// Функція для валідації підпису
+func (sign *SignRSA) ValidateSignatureParams(params *simplejson.Json) bool {
+	return validateSignatureParams(params, sign)
+}
+
+// Функція для підпису параметрів основним ключем, поза періодом його дії - ErrKeyNotValid
+func (keyring *SignKeyring) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	key, err := keyring.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	return signParameters(params, key.Sign)
+}
+
+// Функція для валідації підпису будь-яким чинним ключем
+func (keyring *SignKeyring) ValidateSignatureParams(params *simplejson.Json) bool {
+	for _, key := range keyring.validKeys() {
+		if JSON(key.Sign).ValidateSignatureParams(params) {
+			return true
+		}
+	}
+	return false
+}
+
+func (signPolicy *SignPolicy) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+	release, err := signPolicy.authorize("", "", ParamsFromSimpleJSON(params))
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signParameters(params, signPolicy.sign)
+	if err != nil {
+		release()
+	}
+	return signed, err
+}
+
+// Перевірка підписів політикою не обмежується
+func (signPolicy *SignPolicy) ValidateSignatureParams(params *simplejson.Json) bool {
+	return JSON(signPolicy.sign).ValidateSignatureParams(params)
+}
+
+// Чутливі запити ставляться у чергу і повертають *ApprovalPendingError,
+// інші підписуються одразу
+func (queue *ApprovalQueue) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+	values := ParamsFromSimpleJSON(params)
+	if queue.sensitive(values) {
+		id, err := queue.Submit(values)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ApprovalPendingError{ID: id}
+	}
+	return signParameters(params, queue.sign)
+}
+
+func (queue *ApprovalQueue) ValidateSignatureParams(params *simplejson.Json) bool {
+	return JSON(queue.sign).ValidateSignatureParams(params)
+}
+
+func (sign *SignAudit) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	if params == nil {
+		return nil, ErrNilParams
+	}
+	values, err := paramsMap(params)
+	if err != nil {
+		return nil, err
+	}
+	// У журнал - хеш саме підписаного рядка, без наявного поля підпису
+	signed, err := signParameters(params, sign.sign)
+	if err := sign.record("SignParameters", canonicalString(values, "signature"), err); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (sign *SignAudit) ValidateSignatureParams(params *simplejson.Json) bool {
+	return JSON(sign.sign).ValidateSignatureParams(params)
+}
+
+func (sign *SignInstrumented) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	_, finish := sign.start(sign.ctx, "SignParameters")
+	if params == nil {
+		finish(ResultError, ErrNilParams)
+		return nil, ErrNilParams
+	}
+	signed, err := JSON(sign.sign).SignParameters(params)
+	switch {
+	case err != nil:
+		finish(ResultError, err)
+	case signed.Get("signature").MustString() == "":
+		finish(ResultEmptySignature, ErrEmptySignature)
+	default:
+		finish(ResultOK, nil)
+	}
+	return signed, err
+}
+
+func (sign *SignInstrumented) ValidateSignatureParams(params *simplejson.Json) bool {
+	_, finish := sign.start(sign.ctx, "ValidateSignatureParams")
+	if params == nil {
+		finish(ResultMissingSignature, nil)
+		return false
+	}
+	if _, err := params.Get("signature").String(); err != nil {
+		finish(ResultMissingSignature, nil)
+		return false
+	}
+	valid := JSON(sign.sign).ValidateSignatureParams(params)
+	sign.finishValidation(finish, valid)
+	return valid
+}
+
+func (chain *SignChain) SignParameters(params *simplejson.Json) (*simplejson.Json, error) {
+	values, err := paramsMap(params)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := chain.canonicalize(values, "signature")
+	if err != nil {
+		return nil, err
+	}
+	signature, err := chain.create(context.Background(), canonical)
+	if err != nil {
+		return nil, err
+	}
+	return signedCopy(values, signature), nil
+}
+
+func (chain *SignChain) ValidateSignatureParams(params *simplejson.Json) bool {
+	values, err := paramsMap(params)
+	if err != nil {
+		return false
+	}
+	signature, ok := values["signature"].(string)
+	if !ok {
+		return false
+	}
+	canonical, err := chain.canonicalize(values, "signature")
+	if err != nil {
+		return false
+	}
+	return chain.verify(canonical, signature)
+}
@@ -0,0 +1,62 @@
+package signature_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test 1: Canonical string of simplejson params
+func TestConvertSimpleJSONToString(t *testing.T) {
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	result, err := signature.ConvertSimpleJSONToString(params)
+	assert.Nil(t, err)
+	expected := `timestamp=1610612740000`
+	assert.Equal(t, expected, result)
+}
+
+// Test 2: Signed params are a copy with the signature field
+func TestSignParameters(t *testing.T) {
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signedParams, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	expected := `{"signature":"b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66","timestamp":1610612740000}`
+	result, err := signedParams.MarshalJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(result))
+	assert.Empty(t, params.Get("signature").Interface())
+}
+
+// Sign без SignJSON, як підписувачі поза пакетом signature
+type stringSigner struct {
+	signature.Sign
+	signature.SignContext
+}
+
+// Test 3: JSON adapts any Sign to simplejson params
+func TestJSONAdapter(t *testing.T) {
+	hmac := signature.NewSignHMAC("apy_key", "apy_secret")
+	assert.Same(t, hmac, signature.JSON(hmac))
+
+	sign := signature.JSON(stringSigner{hmac, hmac})
+	params := simplejson.New()
+	params.Set("timestamp", 1610612740000)
+	signed, err := sign.SignParameters(params)
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signed.Get("signature").MustString())
+	assert.True(t, sign.ValidateSignatureParams(signed))
+	assert.True(t, hmac.ValidateSignatureParams(signed))
+
+	// Причина невдачі обгорнутого Sign не губиться
+	hmac.Destroy()
+	_, err = sign.SignParameters(params)
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+	_, err = sign.(signature.SignContext).CreateSignatureContext(context.Background(), "timestamp=1610612740000")
+	assert.ErrorIs(t, err, signature.ErrSignerDestroyed)
+}
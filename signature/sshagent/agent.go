@@ -0,0 +1,169 @@
+package sshagent
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// Підписувач, що використовує ключі Ed25519 або RSA з ssh-agent.
+// Підписи сумісні з signature.SignEd25519.ValidateSignature
+// та signature.SignRSA.ValidateSignature (PKCS#1 v1.5 з SHA-256).
+
+var (
+	ErrNoAgent      = errors.New("SSH_AUTH_SOCK is not set")
+	ErrKeyNotFound  = errors.New("key not found in ssh-agent")
+	ErrKeyType      = errors.New("unsupported ssh-agent key type")
+	ErrBadSignature = errors.New("unexpected ssh-agent signature format")
+)
+
+type SignAgent struct {
+	agent     agent.ExtendedAgent
+	conn      net.Conn
+	key       ssh.PublicKey
+	publicKey crypto.PublicKey
+	apiKey    string
+	destroyed atomic.Bool
+}
+
+// Функція для підключення до агента за SSH_AUTH_SOCK.
+// selector - відбиток ключа (SHA256:... або MD5 у форматі aa:bb:...) чи коментар ключа.
+func NewSignAgent(apiKey, selector string) (*SignAgent, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, ErrNoAgent
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to ssh-agent: %v", err)
+	}
+	sign, err := NewSignAgentWith(agent.NewClient(conn), apiKey, selector)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sign.conn = conn
+	return sign, nil
+}
+
+// Функція для створення підписувача з уже підключеним агентом
+func NewSignAgentWith(sshAgent agent.ExtendedAgent, apiKey, selector string) (*SignAgent, error) {
+	keys, err := sshAgent.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing ssh-agent keys: %v", err)
+	}
+	for _, key := range keys {
+		if key.Comment != selector &&
+			ssh.FingerprintSHA256(key) != selector &&
+			ssh.FingerprintLegacyMD5(key) != selector {
+			continue
+		}
+		publicKey, err := ssh.ParsePublicKey(key.Blob)
+		if err != nil {
+			return nil, err
+		}
+		cryptoKey, ok := publicKey.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrKeyType, key.Type())
+		}
+		sign := &SignAgent{agent: sshAgent, key: publicKey, publicKey: cryptoKey.CryptoPublicKey(), apiKey: apiKey}
+		switch sign.publicKey.(type) {
+		case ed25519.PublicKey, *rsa.PublicKey:
+			return sign, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrKeyType, key.Type())
+	}
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, selector)
+}
+
+// Функція для створення підпису агентом, результат - "сирий" підпис без обгортки SSH
+func (sign *SignAgent) Sign(message []byte) ([]byte, error) {
+	if sign.destroyed.Load() {
+		return nil, signature.ErrSignerDestroyed
+	}
+	var (
+		signature *ssh.Signature
+		err       error
+		format    string
+	)
+	switch sign.publicKey.(type) {
+	case *rsa.PublicKey:
+		signature, err = sign.agent.SignWithFlags(sign.key, message, agent.SignatureFlagRsaSha256)
+		format = ssh.KeyAlgoRSASHA256
+	default:
+		signature, err = sign.agent.Sign(sign.key, message)
+		format = ssh.KeyAlgoED25519
+	}
+	if err != nil {
+		return nil, err
+	}
+	if signature.Format != format {
+		return nil, fmt.Errorf("%w: %s", ErrBadSignature, signature.Format)
+	}
+	return signature.Blob, nil
+}
+
+func (sign *SignAgent) CreateSignature(queryString string) string {
+	signature, _ := sign.CreateSignatureContext(context.Background(), queryString)
+	return signature
+}
+
+// Функція для створення підпису з помилкою агента
+func (sign *SignAgent) CreateSignatureContext(_ context.Context, queryString string) (string, error) {
+	signature, err := sign.Sign([]byte(queryString))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (sign *SignAgent) ValidateSignature(message, signature string) bool {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch publicKey := sign.publicKey.(type) {
+	case *rsa.PublicKey:
+		hashed := sha256.Sum256([]byte(message))
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signatureBytes) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(publicKey, []byte(message), signatureBytes)
+	}
+	return false
+}
+
+func (sign *SignAgent) GetAPIKey() string {
+	return sign.apiKey
+}
+
+// Публічний ключ з агента: ed25519.PublicKey або *rsa.PublicKey
+func (sign *SignAgent) PublicKey() crypto.PublicKey {
+	return sign.publicKey
+}
+
+// Реалізація signature.Destroyer: ключ лишається в агенті, підписувач закриває з'єднання
+// і далі повертає signature.ErrSignerDestroyed
+func (sign *SignAgent) Destroy() {
+	sign.destroyed.Store(true)
+	sign.Close()
+}
+
+// Функція для закриття з'єднання з агентом, відкритого NewSignAgent
+func (sign *SignAgent) Close() error {
+	if sign.conn == nil {
+		return nil
+	}
+	return sign.conn.Close()
+}
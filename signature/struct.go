@@ -0,0 +1,311 @@
+package signature
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Кодування структур запитів у Params за тегами signer:"name,options".
+// Опції тега:
+//
+//	omitempty - пропустити нульове значення; вказівник пропускається лише nil,
+//	            тож *bool(false) та *int(0) кодуються
+//	seconds   - time.Time як Unix секунди (за замовчуванням - мілісекунди)
+//	rfc3339   - time.Time у форматі RFC 3339
+//	repeat    - зріз як повтори ключа (key=a&key=b) замість масиву JSON
+//
+// Тег "-" виключає поле, поле без тега кодується під своїм іменем,
+// вбудовані структури без тега розгортаються. Nil-вказівники пропускаються.
+// Два поля з одним іменем (зокрема з вбудованої структури) - помилка ErrDuplicateField.
+// time.Duration кодується у мілісекундах (recvWindow), типи з encoding.TextMarshaler
+// (decimal.Decimal) або fmt.Stringer (переліки) - своїм текстовим поданням,
+// інші скаляри - як у ConvertSimpleJSONToString, зрізи - масивом JSON, як Params.SetList.
+// Зрізи навмисно кодуються інакше, ніж у ConvertSimpleJSONToString та ParamsFromMap:
+// там []any форматується як fmt %v ([a 1]) заради незмінності наявних підписів,
+// тут - масивом JSON (["a",1]), який очікують біржові API. Для того самого підпису
+// через simplejson список передається рядком з масивом JSON.
+
+type (
+	structField struct {
+		index     []int
+		name      string
+		pointer   bool // вказівник або інтерфейс: omitempty пропускає лише nil
+		omitEmpty bool
+		seconds   bool
+		rfc3339   bool
+		repeat    bool
+	}
+	structFieldsEntry struct {
+		fields []structField
+		err    error
+	}
+)
+
+var (
+	ErrNotStruct       = errors.New("value is not a struct or pointer to struct")
+	ErrUnsupportedType = errors.New("unsupported field type")
+	ErrNilElement      = errors.New("nil slice element")
+	ErrDuplicateField  = errors.New("duplicate params field name")
+
+	structFields  sync.Map // reflect.Type -> structFieldsEntry
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType  = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	byteSliceType = reflect.TypeOf([]byte(nil))
+)
+
+// Функція для кодування структури у Params
+func StructParams(v any) (*Params, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, ErrNotStruct
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	fields, err := cachedStructFields(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	params := NewParams()
+	for _, field := range fields {
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok || field.omitEmpty && !field.pointer && fieldValue.IsZero() {
+			continue
+		}
+		if err := field.encode(params, fieldValue); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.name, err)
+		}
+	}
+	return params, nil
+}
+
+// Функція для підпису структури: повертає закодований запит з полем signature
+// (query string або тіло form-urlencoded) та сам підпис
+func SignStruct(v any, sign Sign) (encoded string, signature string, err error) {
+	params, err := StructParams(v)
+	if err != nil {
+		return
+	}
+	signed, err := SignParams(params, sign)
+	if err != nil {
+		return
+	}
+	signature, _ = signed.Get("signature")
+	return signed.Encode(), signature, nil
+}
+
+func cachedStructFields(t reflect.Type) ([]structField, error) {
+	entry, ok := structFields.Load(t)
+	if !ok {
+		fields := parseStructFields(t, nil)
+		var err error
+		names := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			if names[field.name] {
+				err = fmt.Errorf("%w: %s in %s", ErrDuplicateField, field.name, t)
+				break
+			}
+			names[field.name] = true
+		}
+		entry, _ = structFields.LoadOrStore(t, structFieldsEntry{fields: fields, err: err})
+	}
+	return entry.(structFieldsEntry).fields, entry.(structFieldsEntry).err
+}
+
+func parseStructFields(t reflect.Type, parent []int) (fields []structField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("signer")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int(nil), parent...), i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && !hasTag && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			fields = append(fields, parseStructFields(fieldType, index)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		kind := field.Type.Kind()
+		parsed := structField{index: index, name: name, pointer: kind == reflect.Pointer || kind == reflect.Interface}
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "omitempty":
+				parsed.omitEmpty = true
+			case "seconds":
+				parsed.seconds = true
+			case "rfc3339":
+				parsed.rfc3339 = true
+			case "repeat":
+				parsed.repeat = true
+			}
+		}
+		fields = append(fields, parsed)
+	}
+	return
+}
+
+// Поле за індексом з проходом через вказівники вбудованих структур
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
+
+func (field structField) encode(params *Params, value reflect.Value) error {
+	if value.Kind() == reflect.Slice && value.Type() != byteSliceType && !isTextValue(value.Type()) {
+		if field.repeat {
+			for i := 0; i < value.Len(); i++ {
+				element, err := field.format(value.Index(i))
+				if err != nil {
+					return err
+				}
+				params.Add(field.name, element)
+			}
+			return nil
+		}
+		list, err := field.formatList(value)
+		if err != nil {
+			return err
+		}
+		params.Set(field.name, list)
+		return nil
+	}
+	formatted, err := field.format(value)
+	if err != nil {
+		return err
+	}
+	params.Set(field.name, formatted)
+	return nil
+}
+
+// Масив JSON: рядкові значення у лапках, числа та булеві - як є.
+// Не збігається з форматуванням []any у ConvertSimpleJSONToString, див. опис кодування.
+func (field structField) formatList(value reflect.Value) (string, error) {
+	if value.Len() == 0 {
+		return "[]", nil
+	}
+	var list strings.Builder
+	list.WriteByte('[')
+	for i := 0; i < value.Len(); i++ {
+		if i > 0 {
+			list.WriteByte(',')
+		}
+		element, ok := fieldByIndex(value.Index(i), nil)
+		if !ok {
+			return "", ErrNilElement
+		}
+		formatted, err := field.format(element)
+		if err != nil {
+			return "", err
+		}
+		switch element.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if !isTextValue(element.Type()) && element.Type() != timeType {
+				list.WriteString(formatted)
+				continue
+			}
+		}
+		list.WriteString(strconv.Quote(formatted))
+	}
+	list.WriteByte(']')
+	return list.String(), nil
+}
+
+func isTextValue(t reflect.Type) bool {
+	return t != durationType && (t.Implements(textMarshaler) || t.Implements(stringerType) ||
+		reflect.PointerTo(t).Implements(textMarshaler) || reflect.PointerTo(t).Implements(stringerType))
+}
+
+func (field structField) format(value reflect.Value) (string, error) {
+	value, ok := fieldByIndex(value, nil)
+	if !ok {
+		return "", ErrNilElement
+	}
+	switch value.Type() {
+	case timeType:
+		t := value.Interface().(time.Time)
+		switch {
+		case field.rfc3339:
+			return t.Format(time.RFC3339Nano), nil
+		case field.seconds:
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case durationType:
+		return strconv.FormatInt(value.Interface().(time.Duration).Milliseconds(), 10), nil
+	case byteSliceType:
+		return string(value.Bytes()), nil
+	}
+	if isTextValue(value.Type()) {
+		return textValue(value)
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+}
+
+// Текстове подання через encoding.TextMarshaler або fmt.Stringer,
+// з урахуванням методів з отримувачем-вказівником
+func textValue(value reflect.Value) (string, error) {
+	if !value.Type().Implements(textMarshaler) && !value.Type().Implements(stringerType) {
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		value = pointer
+	}
+	switch v := value.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		return string(text), err
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+}
@@ -0,0 +1,152 @@
+package signature_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderSide int
+
+const (
+	sideBuy orderSide = iota + 1
+	sideSell
+)
+
+func (side orderSide) String() string {
+	return map[orderSide]string{sideBuy: "BUY", sideSell: "SELL"}[side]
+}
+
+type textDecimal struct{ value string }
+
+func (d textDecimal) MarshalText() ([]byte, error) { return []byte(d.value), nil }
+
+type (
+	requestAuth struct {
+		APIKey     string        `signer:"apiKey"`
+		RecvWindow time.Duration `signer:"recvWindow,omitempty"`
+	}
+	orderRequest struct {
+		requestAuth
+		Symbol      string      `signer:"symbol"`
+		Side        orderSide   `signer:"side"`
+		Price       textDecimal `signer:"price"`
+		Quantity    float64     `signer:"quantity"`
+		ReduceOnly  bool        `signer:"reduceOnly,omitempty"`
+		StopPrice   *float64    `signer:"stopPrice"`
+		Symbols     []string    `signer:"symbols,omitempty"`
+		OrderIDs    []int64     `signer:"orderIdList,omitempty"`
+		Tags        []string    `signer:"tag,repeat,omitempty"`
+		Timestamp   time.Time   `signer:"timestamp"`
+		Internal    string      `signer:"-"`
+		unexported  string
+		NewClientID string
+	}
+)
+
+// Test 1: Struct tags are encoded into canonical params
+func TestStructParams(t *testing.T) {
+	request := orderRequest{
+		requestAuth: requestAuth{APIKey: "apy_key", RecvWindow: 5 * time.Second},
+		Symbol:      "BTCUSDT",
+		Side:        sideSell,
+		Price:       textDecimal{"52000.10"},
+		Quantity:    0.01,
+		Symbols:     []string{"BTCUSDT", "ETHUSDT"},
+		OrderIDs:    []int64{1, 2},
+		Tags:        []string{"a", "b"},
+		Timestamp:   time.UnixMilli(1610612740000),
+		Internal:    "skip",
+		unexported:  "skip",
+		NewClientID: "my_order_id_1",
+	}
+	params, err := signature.StructParams(&request)
+	assert.Nil(t, err)
+	assert.Equal(t, "NewClientID=my_order_id_1&apiKey=apy_key&orderIdList=%5B1%2C2%5D&price=52000.10&"+
+		"quantity=0.01&recvWindow=5000&side=SELL&symbol=BTCUSDT&symbols=%5B%22BTCUSDT%22%2C%22ETHUSDT%22%5D&"+
+		"tag=a&tag=b&timestamp=1610612740000", params.Encode())
+
+	type timeFormats struct {
+		Seconds time.Time `signer:"seconds,seconds"`
+		RFC3339 time.Time `signer:"rfc3339,rfc3339"`
+	}
+	moment := time.Date(2021, 1, 14, 8, 25, 40, 0, time.UTC)
+	params, err = signature.StructParams(timeFormats{moment, moment})
+	assert.Nil(t, err)
+	assert.Equal(t, "rfc3339=2021-01-14T08%3A25%3A40Z&seconds=1610612740", params.Encode())
+}
+
+// Test 2: Signing a struct matches signing equivalent Params
+func TestSignStruct(t *testing.T) {
+	type timestampRequest struct {
+		Timestamp int64 `signer:"timestamp"`
+	}
+	sign := signature.NewSignHMAC("apy_key", "apy_secret")
+	encoded, signed, err := signature.SignStruct(timestampRequest{1610612740000}, sign)
+	assert.Nil(t, err)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", signed)
+	assert.Equal(t, "signature="+signed+"&timestamp=1610612740000", encoded)
+}
+
+// Test 3: Unsupported values
+func TestStructParamsErrors(t *testing.T) {
+	_, err := signature.StructParams(42)
+	assert.ErrorIs(t, err, signature.ErrNotStruct)
+	_, err = signature.StructParams((*orderRequest)(nil))
+	assert.ErrorIs(t, err, signature.ErrNotStruct)
+	_, err = signature.StructParams(struct{ Filter map[string]string }{map[string]string{}})
+	assert.ErrorIs(t, err, signature.ErrUnsupportedType)
+	_, err = signature.StructParams(struct{ Prices []*float64 }{[]*float64{nil}})
+	assert.ErrorIs(t, err, signature.ErrNilElement)
+}
+
+// Test 4: omitempty keeps non-nil pointers to zero values, duplicate names are rejected
+func TestStructParamsPointersAndDuplicates(t *testing.T) {
+	reduceOnly, quantity := false, 0
+	params, err := signature.StructParams(struct {
+		ReduceOnly *bool `signer:"reduceOnly,omitempty"`
+		Quantity   *int  `signer:"quantity,omitempty"`
+		Price      *int  `signer:"price,omitempty"`
+		Iceberg    any   `signer:"icebergQty,omitempty"`
+		Side       any   `signer:"side,omitempty"`
+	}{ReduceOnly: &reduceOnly, Quantity: &quantity, Side: ""})
+	assert.Nil(t, err)
+	assert.Equal(t, "quantity=0&reduceOnly=false&side=", params.Encode())
+
+	_, err = signature.StructParams(struct {
+		Symbol string `signer:"symbol"`
+		Pair   string `signer:"symbol"`
+	}{})
+	assert.ErrorIs(t, err, signature.ErrDuplicateField)
+	_, err = signature.StructParams(struct {
+		requestAuth
+		Key string `signer:"apiKey"`
+	}{})
+	assert.ErrorIs(t, err, signature.ErrDuplicateField)
+}
+
+// Test 5: Slices are JSON arrays, unlike []any in ConvertSimpleJSONToString
+func TestStructParamsListEncoding(t *testing.T) {
+	params, err := signature.StructParams(struct {
+		List []any `signer:"list"`
+	}{[]any{"a", 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, "list=%5B%22a%22%2C1%5D", params.Encode())
+
+	// simplejson та map форматують масив як fmt %v
+	js := simplejson.New()
+	js.Set("list", []any{"a", 1})
+	canonical, err := signature.ConvertSimpleJSONToString(js)
+	assert.Nil(t, err)
+	assert.Equal(t, "list=%5Ba+1%5D", canonical)
+	assert.Equal(t, canonical, signature.ParamsFromMap(map[string]any{"list": []any{"a", 1}}).Encode())
+
+	// Той самий канонічний рядок - якщо список передано рядком з масивом JSON
+	js.Set("list", `["a",1]`)
+	canonical, err = signature.ConvertSimpleJSONToString(js)
+	assert.Nil(t, err)
+	assert.Equal(t, params.Encode(), canonical)
+}
@@ -1,15 +1,21 @@
 package signature
 
-import "github.com/bitly/go-simplejson"
+import "context"
 
 type (
 	PublicKey string
 	SecretKey string
-	Sign      interface {
+	// Підпис канонічного рядка. Параметри підписуються через Params (SignParams,
+	// ValidateParams), адаптер simplejson - SignJSON.
+	Sign interface {
 		CreateSignature(queryString string) string
-		SignParameters(params *simplejson.Json) (*simplejson.Json, error)
-		ValidateSignatureParams(params *simplejson.Json) bool
 		ValidateSignature(string, string) bool
 		GetAPIKey() string
 	}
+	// Sign, що повідомляє причину невдачі підпису, яку CreateSignature передає лише
+	// порожнім рядком: знищений ключ, відмова політики, помилка віддаленого підписувача.
+	// SignParams та SignParameters повертають цю помилку, для інших Sign - ErrEmptySignature.
+	SignContext interface {
+		CreateSignatureContext(ctx context.Context, queryString string) (string, error)
+	}
 )
@@ -1,36 +1,114 @@
 package signature
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
-	"net/url"
+	"strings"
+)
 
-	"github.com/bitly/go-simplejson"
+var (
+	ErrParamsNotObject = errors.New("params is not a JSON object")
+	ErrDuplicateKey    = errors.New("duplicate key in params")
 )
 
-func ConvertSimpleJSONToString(js *simplejson.Json) (string, error) {
-	// Парсинг JSON строки
-	values := url.Values{}
-	for key, value := range js.MustMap() {
-		values.Set(key, fmt.Sprintf("%v", value))
+// Глибина вкладеності як у encoding/json
+const maxParamsDepth = 10000
+
+// Значення JSON, об'єкти та масиви розбираються токенами для перевірки повторів ключа
+func decodeParamsValue(decoder *json.Decoder, depth int) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := token.(json.Delim); ok && depth >= maxParamsDepth {
+		return nil, fmt.Errorf("params nested deeper than %d levels", maxParamsDepth)
 	}
+	switch token {
+	case json.Delim('{'):
+		return decodeParamsObject(decoder, depth+1)
+	case json.Delim('['):
+		values := []any{}
+		for decoder.More() {
+			value, err := decodeParamsValue(decoder, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	return token, nil
+}
 
-	return values.Encode(), nil
+// Поля об'єкта після '{' до '}' включно
+func decodeParamsObject(decoder *json.Decoder, depth int) (map[string]any, error) {
+	values := make(map[string]any)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateKey, key)
+		}
+		value, err := decodeParamsValue(decoder, depth)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
-func signParameters(params *simplejson.Json, sign Sign) (*simplejson.Json, error) {
-	// Створення підпису
-	signature, err := ConvertSimpleJSONToString(params)
-	if err != nil {
-		return nil, fmt.Errorf("error encoding params: %v", err)
+// Підпис з причиною невдачі через SignContext, якщо Sign його реалізує,
+// інакше порожній підпис дає ErrEmptySignature
+func createSignature(ctx context.Context, sign Sign, message string) (string, error) {
+	if signer, ok := sign.(SignContext); ok {
+		signature, err := signer.CreateSignatureContext(ctx, message)
+		if err == nil && signature == "" {
+			err = ErrEmptySignature
+		}
+		return signature, err
 	}
-	js, err := params.MarshalJSON()
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling params: %v", err)
+	if signature := sign.CreateSignature(message); signature != "" {
+		return signature, nil
 	}
-	signedParams, err := simplejson.NewJson(js)
-	if err != nil {
-		return nil, fmt.Errorf("error creating new json: %v", err)
+	return "", ErrEmptySignature
+}
+
+// Функція для створення підпису довільним Sign з причиною невдачі: через SignContext,
+// якщо Sign його реалізує, інакше порожній підпис дає ErrEmptySignature
+func CreateSignatureWith(ctx context.Context, sign Sign, message string) (string, error) {
+	return createSignature(ctx, sign, message)
+}
+
+// Перший блок PEM заданого типу. Після нього допускаються лише інші блоки PEM
+// (напр. дописаний сертифікат), будь-які інші дані відхиляються.
+func decodePEMBlock(content, blockType string) (*pem.Block, error) {
+	block, rest := pem.Decode([]byte(content))
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("failed to decode PEM block containing %s", strings.ToLower(blockType))
+	}
+	// pem.Decode пропускає дані перед блоком, тому кожен наступний блок має починатися одразу
+	for rest = bytes.TrimSpace(rest); len(rest) > 0; rest = bytes.TrimSpace(rest) {
+		var next *pem.Block
+		if bytes.HasPrefix(rest, []byte("-----BEGIN ")) {
+			next, rest = pem.Decode(rest)
+		}
+		if next == nil {
+			return nil, fmt.Errorf("unexpected data after PEM block containing %s", strings.ToLower(blockType))
+		}
 	}
-	signedParams.Set("signature", sign.CreateSignature(signature))
-	return signedParams, nil
+	return block, nil
 }
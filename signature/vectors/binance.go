@@ -0,0 +1,37 @@
+package vectors
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/fr0ster/turbo-signer/signature"
+)
+
+// Профіль binance: HMAC-SHA256 над query string у порядку параметрів запиту,
+// як у прикладі документації Binance API. Без order ключі сортуються.
+const ProfileBinance = "binance"
+
+func init() {
+	if err := RegisterProfile(ProfileBinance, Profile{Canonical: binanceCanonical}); err != nil {
+		panic(err)
+	}
+}
+
+func binanceCanonical(params map[string]any, order []string) (string, error) {
+	if len(order) == 0 {
+		order = sortedKeys(params)
+	}
+	var builder strings.Builder
+	for _, key := range order {
+		if key == "signature" {
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteByte('&')
+		}
+		builder.WriteString(url.QueryEscape(key))
+		builder.WriteByte('=')
+		builder.WriteString(url.QueryEscape(signature.FormatParamValue(params[key])))
+	}
+	return builder.String(), nil
+}
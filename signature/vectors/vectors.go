@@ -0,0 +1,374 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"gopkg.in/yaml.v3"
+)
+
+// Еталонні тестові вектори підписів (golden vectors) у JSON або YAML:
+//
+//	vectors:
+//	  - name: binance order
+//	    algorithm: hmac-sha256         # назва як у signature.Algorithm()
+//	    profile: ""                    # профіль біржі, порожній - канонічний рядок url.Values
+//	    key: {api_key: apy_key, secret_key: apy_secret}
+//	    params: {timestamp: "1610612740000"}
+//	    order: [timestamp]             # порядок ключів для профілів, що його враховують
+//	    canonical: timestamp=1610612740000
+//	    signature: b9739a6b...
+//
+// Числа у params YAML задаються рядками: YAML перетворює їх на int/float,
+// тож 1.10 стало б 1.1, такі вектори відхиляються. JSON зберігає числа як є (json.Number).
+// Ключі у векторах - лише тестові!
+// Профілі бірж з власною канонізацією чи підписувачем реєструються RegisterProfile.
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var (
+	ErrMismatch      = errors.New("test vector mismatch")
+	ErrAlgorithm     = errors.New("unknown test vector algorithm")
+	ErrProfile       = errors.New("unknown test vector profile")
+	ErrProfileExists = errors.New("test vector profile already registered")
+	ErrOrder         = errors.New("test vector order does not match params")
+	ErrNumber        = errors.New("test vector param is a YAML number, quote it")
+)
+
+type (
+	Vector struct {
+		Name      string         `json:"name" yaml:"name"`
+		Algorithm string         `json:"algorithm" yaml:"algorithm"`
+		Profile   string         `json:"profile,omitempty" yaml:"profile,omitempty"`
+		Key       Key            `json:"key" yaml:"key"`
+		Params    map[string]any `json:"params" yaml:"params"`
+		// Порядок ключів params у запиті, порожній - не задано.
+		// Якщо заданий, містить кожен ключ params рівно один раз.
+		Order     []string `json:"order,omitempty" yaml:"order,omitempty"`
+		Canonical string   `json:"canonical" yaml:"canonical"`
+		Signature string   `json:"signature" yaml:"signature"`
+	}
+	// Ключовий матеріал вектора, PEM ключі - PKCS#8 та PKIX.
+	// signature.Credential не підходить: його JSON маскує секрети.
+	Key struct {
+		APIKey     string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+		SecretKey  string `json:"secret_key,omitempty" yaml:"secret_key,omitempty"`
+		PublicKey  string `json:"public_key,omitempty" yaml:"public_key,omitempty"`
+		PrivateKey string `json:"private_key,omitempty" yaml:"private_key,omitempty"`
+		Context    string `json:"context,omitempty" yaml:"context,omitempty"` // контекст ed25519ph/ed25519ctx
+	}
+	File struct {
+		Vectors []Vector `json:"vectors" yaml:"vectors"`
+	}
+	// Профіль біржі для векторів, незадані функції - поведінка за замовчуванням
+	Profile struct {
+		// Канонічний рядок параметрів, order - Vector.Order.
+		// nil - signature.CanonicalString без поля signature, order не враховується.
+		Canonical func(params map[string]any, order []string) (string, error)
+		// Підписувач для ключа вектора, nil - вбудований за назвою алгоритму
+		Signer func(algorithm string, key Key) (signature.Sign, error)
+	}
+)
+
+var profiles = struct {
+	mutex    sync.RWMutex
+	profiles map[string]Profile
+}{profiles: make(map[string]Profile)}
+
+// Функція для реєстрації профілю біржі, зазвичай з init() пакета профілю
+func RegisterProfile(name string, profile Profile) error {
+	profiles.mutex.Lock()
+	defer profiles.mutex.Unlock()
+	if _, ok := profiles.profiles[name]; ok || name == "" {
+		return fmt.Errorf("%w: %q", ErrProfileExists, name)
+	}
+	profiles.profiles[name] = profile
+	return nil
+}
+
+// Назви зареєстрованих профілів
+func Profiles() []string {
+	profiles.mutex.RLock()
+	defer profiles.mutex.RUnlock()
+	return sortedKeys(profiles.profiles)
+}
+
+func lookupProfile(name string) (Profile, error) {
+	if name == "" {
+		return Profile{}, nil
+	}
+	profiles.mutex.RLock()
+	defer profiles.mutex.RUnlock()
+	profile, ok := profiles.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q", ErrProfile, name)
+	}
+	return profile, nil
+}
+
+// Функція для створення вбудованого підписувача за назвою алгоритму (як у signature.Algorithm)
+func Signer(algorithm string, key Key) (signature.Sign, error) {
+	switch algorithm {
+	case signature.HTTPSigHMACSHA256:
+		return signature.NewSignHMAC(signature.PublicKey(key.APIKey), signature.SecretKey(key.SecretKey)), nil
+	case signature.HTTPSigRSAv15SHA256:
+		return signature.NewSignRSA(key.APIKey, key.PublicKey, key.PrivateKey)
+	case signature.HTTPSigEd25519, "ed25519ph", "ed25519ctx":
+		sign, err := signature.NewSignEd25519(key.APIKey, key.PublicKey, key.PrivateKey)
+		switch {
+		case err != nil:
+			return nil, err
+		case algorithm == "ed25519ph":
+			return sign.WithOptions(signature.Ed25519phOptions(key.Context)), nil
+		case algorithm == "ed25519ctx" && key.Context == "":
+			return nil, signature.ErrEd25519Context
+		case algorithm == "ed25519ctx":
+			return sign.WithOptions(signature.Ed25519ctxOptions(key.Context)), nil
+		}
+		return sign, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrAlgorithm, algorithm)
+}
+
+// Функція для створення вектора з перевірених вхідних даних (назва, алгоритм,
+// профіль, ключ, параметри та порядок): канонічний рядок та підпис обчислюються
+// і записуються у вектор
+func Generate(vector Vector) (Vector, error) {
+	sign, canonical, err := vector.prepare()
+	if err != nil {
+		return vector, err
+	}
+	vector.Canonical = canonical
+	vector.Signature = sign.CreateSignature(canonical)
+	if vector.Signature == "" {
+		return vector, signature.ErrEmptySignature
+	}
+	return vector, vector.Verify()
+}
+
+// Функція для перевірки вектора: канонічний рядок, перевірка еталонного підпису,
+// повторне створення підпису (алгоритм має бути детермінованим) та, для профілю
+// за замовчуванням, SignParams
+func (vector Vector) Verify() error {
+	sign, canonical, err := vector.prepare()
+	if err != nil {
+		return err
+	}
+	if canonical != vector.Canonical {
+		return fmt.Errorf("%w: %s: canonical %q, expected %q", ErrMismatch, vector.Name, canonical, vector.Canonical)
+	}
+	if !sign.ValidateSignature(canonical, vector.Signature) {
+		return fmt.Errorf("%w: %s: expected signature does not verify", ErrMismatch, vector.Name)
+	}
+	if signature := sign.CreateSignature(canonical); signature != vector.Signature {
+		return fmt.Errorf("%w: %s: signature %q, expected %q", ErrMismatch, vector.Name, signature, vector.Signature)
+	}
+	if vector.Profile != "" {
+		return nil
+	}
+	signed, err := signature.SignParams(signature.ParamsFromMap(vector.Params), sign)
+	if err != nil {
+		return fmt.Errorf("%s: %w", vector.Name, err)
+	}
+	if signature, _ := signed.Get("signature"); signature != vector.Signature {
+		return fmt.Errorf("%w: %s: SignParams signature %q, expected %q", ErrMismatch, vector.Name, signature, vector.Signature)
+	}
+	return nil
+}
+
+func (vector Vector) prepare() (signature.Sign, string, error) {
+	profile, err := lookupProfile(vector.Profile)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := vector.checkOrder(); err != nil {
+		return nil, "", err
+	}
+	signer := profile.Signer
+	if signer == nil {
+		signer = Signer
+	}
+	sign, err := signer(vector.Algorithm, vector.Key)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", vector.Name, err)
+	}
+	if profile.Canonical == nil {
+		params := make(map[string]any, len(vector.Params))
+		for key, value := range vector.Params {
+			if key != "signature" {
+				params[key] = value
+			}
+		}
+		return sign, signature.CanonicalString(params), nil
+	}
+	canonical, err := profile.Canonical(vector.Params, vector.Order)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", vector.Name, err)
+	}
+	return sign, canonical, nil
+}
+
+// Заданий порядок містить кожен ключ params рівно один раз
+func (vector Vector) checkOrder() error {
+	if len(vector.Order) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(vector.Order))
+	for _, key := range vector.Order {
+		if _, ok := vector.Params[key]; !ok || seen[key] {
+			return fmt.Errorf("%w: %s: key %q", ErrOrder, vector.Name, key)
+		}
+		seen[key] = true
+	}
+	if len(seen) != len(vector.Params) {
+		return fmt.Errorf("%w: %s: %d of %d keys", ErrOrder, vector.Name, len(seen), len(vector.Params))
+	}
+	return nil
+}
+
+// Функція для читання файлу векторів, формат визначається розширенням (.yaml, .yml або JSON)
+func Load(path string) ([]Vector, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := Decode(content, FileFormat(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vectors, nil
+}
+
+func Decode(content []byte, format string) ([]Vector, error) {
+	var file File
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil && err != io.EOF {
+			return nil, err
+		}
+		for _, vector := range file.Vectors {
+			if err := checkNumbers(vector.Name, vector.Params); err != nil {
+				return nil, err
+			}
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		decoder.UseNumber()
+		if err := decoder.Decode(&file); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown test vector format %q", format)
+	}
+	return file.Vectors, nil
+}
+
+// Числа YAML вже втратили запис з файлу (1.10, 1e3, 0x10), тому відхиляються
+func checkNumbers(name string, value any) error {
+	switch value := value.(type) {
+	case int, int64, uint64, float64:
+		return fmt.Errorf("%w: %s: %v", ErrNumber, name, value)
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			if err := checkNumbers(name, value[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if err := checkNumbers(name, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// У YAML числа з JSON (json.Number) записуються рядками, щоб пройти Decode
+func Encode(w io.Writer, vectors []Vector, format string) error {
+	switch format {
+	case FormatYAML:
+		quoted := make([]Vector, len(vectors))
+		for i, vector := range vectors {
+			quoted[i] = vector
+			quoted[i].Params = quoteNumbers(vector.Params).(map[string]any)
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(File{Vectors: quoted}); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(File{Vectors: vectors})
+	}
+	return fmt.Errorf("unknown test vector format %q", format)
+}
+
+// Копія значення з числами, записаними як у канонічному рядку
+func quoteNumbers(value any) any {
+	switch value := value.(type) {
+	case json.Number, int, int64, uint64, float64:
+		return signature.FormatParamValue(value)
+	case map[string]any:
+		quoted := make(map[string]any, len(value))
+		for key, item := range value {
+			quoted[key] = quoteNumbers(item)
+		}
+		return quoted
+	case []any:
+		quoted := make([]any, len(value))
+		for i, item := range value {
+			quoted[i] = quoteNumbers(item)
+		}
+		return quoted
+	}
+	return value
+}
+
+// Формат файлу векторів за розширенням
+func FileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// Функція для перевірки векторів, повертає помилки всіх невдалих векторів
+func VerifyAll(vectors []Vector) error {
+	var errs []error
+	for _, vector := range vectors {
+		if err := vector.Verify(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
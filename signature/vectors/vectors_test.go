@@ -0,0 +1,167 @@
+package vectors_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fr0ster/turbo-signer/signature"
+	"github.com/fr0ster/turbo-signer/signature/signaturetest"
+	"github.com/fr0ster/turbo-signer/signature/vectors"
+	"github.com/stretchr/testify/assert"
+)
+
+func hmacVector(name string, params map[string]any) vectors.Vector {
+	return vectors.Vector{
+		Name:      name,
+		Algorithm: "hmac-sha256",
+		Key:       vectors.Key{APIKey: "apy_key", SecretKey: "apy_secret"},
+		Params:    params,
+	}
+}
+
+// Test 1: Generated vectors verify and survive JSON and YAML round trips
+func TestGenerate(t *testing.T) {
+	vector, err := vectors.Generate(hmacVector("hmac", map[string]any{"timestamp": "1610612740000"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "timestamp=1610612740000", vector.Canonical)
+	assert.Equal(t, "b9739a6b6322ff0490293f52807fc895cddf41cdb34c178b346589148fec3b66", vector.Signature)
+
+	ctxVector, err := vectors.Generate(vectors.Vector{
+		Name:      "ed25519ctx",
+		Algorithm: "ed25519ctx",
+		Key:       vectors.Key{PublicKey: signaturetest.Ed25519PublicKeyPEM, PrivateKey: signaturetest.Ed25519PrivateKeyPEM, Context: "ctx"},
+		Params:    map[string]any{"a": "1"},
+	})
+	assert.Nil(t, err)
+
+	for _, format := range []string{vectors.FormatJSON, vectors.FormatYAML} {
+		var buffer bytes.Buffer
+		assert.Nil(t, vectors.Encode(&buffer, []vectors.Vector{vector, ctxVector}, format))
+		decoded, err := vectors.Decode(buffer.Bytes(), format)
+		assert.Nil(t, err, format)
+		assert.Len(t, decoded, 2)
+		assert.Nil(t, vectors.VerifyAll(decoded), format)
+	}
+
+	// Без контексту ed25519ctx був би чистим Ed25519
+	emptyContext := ctxVector
+	emptyContext.Key.Context = ""
+	_, err = vectors.Generate(emptyContext)
+	assert.ErrorIs(t, err, signature.ErrEd25519Context)
+	emptyContext.Signature = ctxVector.Signature
+	assert.ErrorIs(t, emptyContext.Verify(), signature.ErrEd25519Context)
+
+	unknown := hmacVector("unknown", nil)
+	unknown.Algorithm = "hmac-sha512"
+	_, err = vectors.Generate(unknown)
+	assert.ErrorIs(t, err, vectors.ErrAlgorithm)
+	profile := hmacVector("profile", nil)
+	profile.Profile = "no-such-venue"
+	_, err = vectors.Generate(profile)
+	assert.ErrorIs(t, err, vectors.ErrProfile)
+}
+
+// Test 2: Mismatching vectors are reported
+func TestVerifyMismatch(t *testing.T) {
+	vector, err := vectors.Generate(hmacVector("hmac", map[string]any{"timestamp": "1610612740000"}))
+	assert.Nil(t, err)
+
+	canonical := vector
+	canonical.Canonical = "timestamp=1"
+	signatureMismatch := vector
+	signatureMismatch.Signature = strings.Repeat("0", 64)
+	params := vector
+	params.Params = map[string]any{"timestamp": "1610612740001"}
+	for _, broken := range []vectors.Vector{canonical, signatureMismatch, params} {
+		assert.ErrorIs(t, broken.Verify(), vectors.ErrMismatch)
+	}
+	err = vectors.VerifyAll([]vectors.Vector{vector, canonical, params})
+	assert.ErrorIs(t, err, vectors.ErrMismatch)
+	assert.Equal(t, 2, strings.Count(err.Error(), "test vector mismatch"))
+}
+
+// Test 3: Registered venue profiles define canonicalization and signer
+func TestProfile(t *testing.T) {
+	name := fmt.Sprintf("header-venue-%p", t)
+	assert.Nil(t, vectors.RegisterProfile(name, vectors.Profile{
+		// timestamp + method + path + body, як у бірж з підписом заголовків
+		Canonical: func(params map[string]any, _ []string) (string, error) {
+			return fmt.Sprint(params["timestamp"], params["method"], params["path"], params["body"]), nil
+		},
+	}))
+	assert.ErrorIs(t, vectors.RegisterProfile(name, vectors.Profile{}), vectors.ErrProfileExists)
+	assert.ErrorIs(t, vectors.RegisterProfile(vectors.ProfileBinance, vectors.Profile{}), vectors.ErrProfileExists)
+	assert.Contains(t, vectors.Profiles(), name)
+	assert.Contains(t, vectors.Profiles(), vectors.ProfileBinance)
+
+	vector := hmacVector("venue balance", map[string]any{"timestamp": "2020-12-08T09:08:57.715Z", "method": "GET", "path": "/api/v5/account/balance", "body": ""})
+	vector.Profile = name
+	vector, err := vectors.Generate(vector)
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-12-08T09:08:57.715ZGET/api/v5/account/balance", vector.Canonical)
+	assert.Nil(t, vector.Verify())
+}
+
+// Test 4: Vector files are loaded by extension
+func TestLoad(t *testing.T) {
+	vector, err := vectors.Generate(hmacVector("hmac", map[string]any{"timestamp": "1610612740000"}))
+	assert.Nil(t, err)
+	dir := t.TempDir()
+	for _, name := range []string{"vectors.json", "vectors.yml"} {
+		var buffer bytes.Buffer
+		assert.Nil(t, vectors.Encode(&buffer, []vectors.Vector{vector}, vectors.FileFormat(name)))
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+		loaded, err := vectors.Load(path)
+		assert.Nil(t, err)
+		assert.Nil(t, vectors.VerifyAll(loaded))
+	}
+	path := filepath.Join(dir, "unknown.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"vectors":[{"name":"x","extra":1}]}`), 0o600))
+	_, err = vectors.Load(path)
+	assert.NotNil(t, err)
+}
+
+// Test 5: YAML numbers in params are rejected, JSON numbers are written to YAML as strings
+func TestYAMLNumbers(t *testing.T) {
+	for _, value := range []string{"1610612740000", "1.10", "1e3", "0x10", "[1]"} {
+		_, err := vectors.Decode([]byte("vectors:\n  - name: n\n    params: {timestamp: "+value+"}\n"), vectors.FormatYAML)
+		assert.ErrorIs(t, err, vectors.ErrNumber, value)
+	}
+	loaded, err := vectors.Decode([]byte(`{"vectors":[{"name":"hmac","algorithm":"hmac-sha256","key":{"secret_key":"apy_secret"},`+
+		`"params":{"price":1.10,"timestamp":1610612740000},"canonical":"price=1.10&timestamp=1610612740000","signature":""}]}`), vectors.FormatJSON)
+	assert.Nil(t, err)
+	vector, err := vectors.Generate(loaded[0])
+	assert.Nil(t, err)
+	var buffer bytes.Buffer
+	assert.Nil(t, vectors.Encode(&buffer, []vectors.Vector{vector}, vectors.FormatYAML))
+	assert.Contains(t, buffer.String(), `price: "1.10"`)
+	decoded, err := vectors.Decode(buffer.Bytes(), vectors.FormatYAML)
+	assert.Nil(t, err)
+	assert.Nil(t, vectors.VerifyAll(decoded))
+}
+
+// Test 6: Binance profile signs params in request order, order must match params
+func TestBinanceProfile(t *testing.T) {
+	vector := hmacVector("binance", map[string]any{"timestamp": "1610612740000", "symbol": "BTCUSDT"})
+	vector.Profile = vectors.ProfileBinance
+	vector.Order = []string{"timestamp", "symbol"}
+	vector, err := vectors.Generate(vector)
+	assert.Nil(t, err)
+	assert.Equal(t, "timestamp=1610612740000&symbol=BTCUSDT", vector.Canonical)
+
+	// Без order - відсортовані ключі, як у профілі за замовчуванням
+	vector.Order = nil
+	vector, err = vectors.Generate(vector)
+	assert.Nil(t, err)
+	assert.Equal(t, "symbol=BTCUSDT&timestamp=1610612740000", vector.Canonical)
+
+	for _, order := range [][]string{{"timestamp"}, {"timestamp", "timestamp"}, {"timestamp", "symbol", "side"}} {
+		vector.Order = order
+		assert.ErrorIs(t, vector.Verify(), vectors.ErrOrder, order)
+	}
+}